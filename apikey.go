@@ -0,0 +1,82 @@
+package cloudant
+
+import (
+	"encoding/json"
+)
+
+// APIKey is a Cloudant API key and its password
+type APIKey struct {
+	Key      string `json:"key"`
+	Password string `json:"password"`
+}
+
+// GenerateAPIKey generates a new Cloudant API key. The key has no access to
+// any database until it is granted permissions, see CreateAPIKey().
+// See: https://console.bluemix.net/docs/services/Cloudant/api/authorization.html#creating-api-keys
+func (c *CouchClient) GenerateAPIKey() (*APIKey, error) {
+	urlStr, err := Endpoint(*c.rootURL, "/_api/v2/api_keys", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := c.request("POST", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200, 201)
+	if err != nil {
+		return nil, err
+	}
+
+	apiKey := &APIKey{}
+	err = json.NewDecoder(job.response.Body).Decode(apiKey)
+
+	return apiKey, err
+}
+
+// CreateAPIKey generates a new Cloudant API key and grants it permissions on
+// each of the given databases. If a grant fails the key is returned along
+// with the error, so that it can be revoked.
+//
+// Example:
+//
+//	apiKey, err := client.CreateAPIKey([]string{cloudant.PermissionReader}, "orders", "customers")
+func (c *CouchClient) CreateAPIKey(permissions []string, databaseNames ...string) (*APIKey, error) {
+	apiKey, err := c.GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, databaseName := range databaseNames {
+		database, err := c.Get(databaseName)
+		if err != nil {
+			return apiKey, err
+		}
+
+		if err = database.GrantPermissions(apiKey.Key, permissions...); err != nil {
+			return apiKey, err
+		}
+	}
+
+	return apiKey, nil
+}
+
+// RevokeAPIKey removes all permissions of an API key on each of the given
+// databases. Cloudant doesn't delete API keys, but a key without permissions
+// can't access any database.
+func (c *CouchClient) RevokeAPIKey(key string, databaseNames ...string) error {
+	for _, databaseName := range databaseNames {
+		database, err := c.Get(databaseName)
+		if err != nil {
+			return err
+		}
+
+		if err = database.RevokePermissions(key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
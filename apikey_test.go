@@ -0,0 +1,100 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// apiKeyServer is an httptest stand-in for the Cloudant API key and
+// security endpoints.
+type apiKeyServer struct {
+	mu       sync.Mutex
+	keys     int
+	security map[string]*Security
+}
+
+func (s *apiKeyServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch {
+	case r.URL.Path == "/_session":
+		fmt.Fprint(w, `{"ok":true}`)
+	case r.URL.Path == "/_api/v2/api_keys" && r.Method == "POST":
+		s.keys++
+		w.WriteHeader(201)
+		fmt.Fprintf(w, `{"password":"secret-%d","ok":true,"key":"key-%d"}`, s.keys, s.keys)
+	case r.URL.Path == "/db-1/_security" || r.URL.Path == "/db-2/_security":
+		name := r.URL.Path[1:5]
+		if r.Method == "PUT" {
+			body, _ := ioutil.ReadAll(r.Body)
+			security := &Security{}
+			if err := json.Unmarshal(body, security); err != nil {
+				w.WriteHeader(400)
+				return
+			}
+			s.security[name] = security
+			fmt.Fprint(w, `{"ok":true}`)
+			return
+		}
+		security, ok := s.security[name]
+		if !ok {
+			security = &Security{}
+		}
+		json.NewEncoder(w).Encode(security)
+	default:
+		w.WriteHeader(404)
+		fmt.Fprint(w, `{"error":"not_found","reason":"missing"}`)
+	}
+}
+
+func TestClient_APIKeys(t *testing.T) {
+	stub := &apiKeyServer{security: map[string]*Security{
+		"db-1": {Admins: SecurityGroup{Names: []string{"admin"}}},
+	}}
+	server := httptest.NewServer(stub)
+	defer server.Close()
+
+	client, err := CreateClientWithRetry("user", "pass", server.URL, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	apiKey, err := client.CreateAPIKey([]string{PermissionReader, PermissionWriter}, "db-1", "db-2")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if apiKey.Key != "key-1" || apiKey.Password != "secret-1" {
+		t.Errorf("unexpected API key %+v", apiKey)
+	}
+
+	for _, name := range []string{"db-1", "db-2"} {
+		granted := stub.security[name].Cloudant["key-1"]
+		if len(granted) != 2 || granted[0] != PermissionReader || granted[1] != PermissionWriter {
+			t.Errorf("unexpected permissions on %s: %v", name, granted)
+		}
+	}
+	if names := stub.security["db-1"].Admins.Names; len(names) != 1 || names[0] != "admin" {
+		t.Errorf("expected the admins of db-1 to be kept, got %v", names)
+	}
+
+	if err = client.RevokeAPIKey(apiKey.Key, "db-1", "db-2"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	for _, name := range []string{"db-1", "db-2"} {
+		if _, ok := stub.security[name].Cloudant[apiKey.Key]; ok {
+			t.Errorf("expected the permissions on %s to be revoked", name)
+		}
+	}
+
+	_, err = client.CreateAPIKey([]string{PermissionReader}, "db-3")
+	if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 {
+		t.Errorf("expected a 404 for a missing database, got %v", err)
+	}
+}
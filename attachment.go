@@ -0,0 +1,203 @@
+package cloudant
+
+import (
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"hash"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// AttachmentMeta represents the meta-data of an attachment, as returned in
+// the headers of a GET or HEAD request
+type AttachmentMeta struct {
+	ContentType   string
+	ContentLength int64  // -1 if unknown
+	Digest        string // "md5-" followed by the base64-encoded MD5, if sent by the server
+}
+
+// Attachment is an attachment being downloaded. Body must always be closed.
+type Attachment struct {
+	AttachmentMeta
+	Body   io.ReadCloser
+	reader *attachmentReader
+}
+
+// attachmentReader hashes the attachment as it is read from the response.
+type attachmentReader struct {
+	job  *Job
+	hash hash.Hash
+	eof  bool
+}
+
+func (r *attachmentReader) Read(p []byte) (int, error) {
+	n, err := r.job.response.Body.Read(p)
+	r.hash.Write(p[:n])
+	if err == io.EOF {
+		r.eof = true
+	}
+	return n, err
+}
+
+func (r *attachmentReader) Close() error {
+	r.job.Close()
+	return nil
+}
+
+// VerifyDigest checks the MD5 digest of the downloaded data against the one
+// sent by the server. It must be called once Body has been read to the end.
+func (a *Attachment) VerifyDigest() error {
+	if !a.reader.eof {
+		return fmt.Errorf("attachment has not been read to the end")
+	}
+	if !strings.HasPrefix(a.Digest, "md5-") {
+		return fmt.Errorf("no MD5 digest sent by the server")
+	}
+
+	digest := "md5-" + base64.StdEncoding.EncodeToString(a.reader.hash.Sum(nil))
+	if digest != a.Digest {
+		return fmt.Errorf("attachment digest mismatch: expected %s, got %s", a.Digest, digest)
+	}
+
+	return nil
+}
+
+// attachmentMeta reads the attachment meta-data from the response headers.
+func attachmentMeta(resp *http.Response) AttachmentMeta {
+	meta := AttachmentMeta{
+		ContentType:   resp.Header.Get("Content-Type"),
+		ContentLength: resp.ContentLength,
+	}
+
+	if md5sum := resp.Header.Get("Content-MD5"); md5sum != "" {
+		meta.Digest = "md5-" + md5sum
+	} else if etag := strings.Trim(resp.Header.Get("ETag"), `"`); etag != "" {
+		meta.Digest = "md5-" + etag
+	}
+
+	return meta
+}
+
+// attachmentURL builds the URL of a document's attachment.
+func (d *Database) attachmentURL(documentID, name, rev string) (string, error) {
+	var params url.Values
+	if rev != "" {
+		params = url.Values{"rev": []string{rev}}
+	}
+
+	return Endpoint(*d.URL, documentID+"/"+name, params)
+}
+
+// PutAttachment uploads an attachment to a document with a specified
+// revision, creating the document if rev is empty. The body is streamed to
+// the server as it is read. If it implements io.Seeker failed uploads are
+// retried, otherwise they aren't.
+func (d *Database) PutAttachment(documentID, rev, name, contentType string, body io.Reader) (*DocumentMeta, error) {
+	urlStr, err := d.attachmentURL(documentID, name, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{"Content-Type": []string{contentType}}
+
+	job, err := d.client.requestStream("PUT", urlStr, body, header)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 201, 202)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &DocumentMeta{}
+	err = json.NewDecoder(job.response.Body).Decode(meta)
+
+	return meta, err
+}
+
+// GetAttachment downloads the latest revision of a document's attachment.
+// The data is read from the server as Body is read, and Body must be closed
+// once done with.
+func (d *Database) GetAttachment(documentID, name string) (*Attachment, error) {
+	urlStr, err := d.attachmentURL(documentID, name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("GET", urlStr, nil)
+	if err != nil {
+		job.Close()
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		job.Close()
+		return nil, err
+	}
+
+	reader := &attachmentReader{job: job, hash: md5.New()}
+
+	return &Attachment{
+		AttachmentMeta: attachmentMeta(job.response),
+		Body:           reader,
+		reader:         reader,
+	}, nil
+}
+
+// AttachmentInfo returns the meta-data of a document's attachment without
+// downloading it.
+func (d *Database) AttachmentInfo(documentID, name string) (*AttachmentMeta, error) {
+	urlStr, err := d.attachmentURL(documentID, name, "")
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("HEAD", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if job.response.StatusCode != 200 {
+		return nil, &CouchError{
+			Err:        strings.ToLower(strings.Replace(http.StatusText(job.response.StatusCode), " ", "_", -1)),
+			StatusCode: job.response.StatusCode,
+		}
+	}
+
+	meta := attachmentMeta(job.response)
+
+	return &meta, nil
+}
+
+// DeleteAttachment removes an attachment from a document with a specified
+// revision.
+func (d *Database) DeleteAttachment(documentID, rev, name string) (*DocumentMeta, error) {
+	urlStr, err := d.attachmentURL(documentID, name, rev)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("DELETE", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200, 202)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &DocumentMeta{}
+	err = json.NewDecoder(job.response.Body).Decode(meta)
+
+	return meta, err
+}
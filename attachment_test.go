@@ -0,0 +1,193 @@
+package cloudant
+
+import (
+	"bytes"
+	"crypto/md5"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestAttachment_VerifyDigest(t *testing.T) {
+	resp := &http.Response{
+		Header:        http.Header{},
+		ContentLength: 5,
+		Body:          ioutil.NopCloser(strings.NewReader("hello")),
+	}
+	resp.Header.Set("Content-Type", "text/plain")
+	resp.Header.Set("ETag", `"XUFAKrxLKna5cZ2REBfFkg=="`)
+
+	reader := &attachmentReader{job: &Job{response: resp}, hash: md5.New()}
+	att := &Attachment{AttachmentMeta: attachmentMeta(resp), Body: reader, reader: reader}
+
+	if att.Digest != "md5-XUFAKrxLKna5cZ2REBfFkg==" || att.ContentType != "text/plain" {
+		t.Errorf("unexpected meta-data %+v", att.AttachmentMeta)
+	}
+	if err := att.VerifyDigest(); err == nil {
+		t.Error("expected an error before the attachment is read")
+	}
+
+	data, _ := ioutil.ReadAll(att.Body)
+	if string(data) != "hello" {
+		t.Errorf("unexpected data '%s'", data)
+	}
+	if err := att.VerifyDigest(); err != nil {
+		t.Error(err)
+	}
+
+	att.Digest = "md5-AAAAAAAAAAAAAAAAAAAAAA=="
+	if err := att.VerifyDigest(); err == nil {
+		t.Error("expected a digest mismatch")
+	}
+}
+
+func TestStreamLength(t *testing.T) {
+	if length, known := streamLength(strings.NewReader("abc"), 0); !known || length != 3 {
+		t.Errorf("unexpected length %d, %v", length, known)
+	}
+	if length, known := streamLength(&bytes.Buffer{}, 0); !known || length != 0 {
+		t.Errorf("expected a known empty length, got %d, %v", length, known)
+	}
+	if _, known := streamLength(ioutil.NopCloser(strings.NewReader("abc")), 0); known {
+		t.Errorf("expected an unknown length")
+	}
+
+	file, err := ioutil.TempFile("", "attachment")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer os.Remove(file.Name())
+	defer file.Close()
+
+	file.WriteString("abcdef")
+	if length, known := streamLength(file, 2); !known || length != 4 {
+		t.Errorf("unexpected file length %d, %v", length, known)
+	}
+	if length, known := streamLength(file, 6); !known || length != 0 {
+		t.Errorf("expected a known empty file length, got %d, %v", length, known)
+	}
+}
+
+func TestDatabase_PutAttachmentEmpty(t *testing.T) {
+	received := make(chan *http.Request, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_session" {
+			return
+		}
+		received <- r
+		w.WriteHeader(201)
+		fmt.Fprint(w, `{"ok":true,"id":"doc-1","rev":"2-abc"}`)
+	}))
+	defer server.Close()
+
+	client, err := CreateClientWithRetry("user", "pass", server.URL, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	database, err := client.Get("db")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	_, err = database.PutAttachment("doc-1", "1-abc", "empty.txt", "text/plain", &bytes.Buffer{})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	r := <-received
+	if len(r.TransferEncoding) > 0 || r.ContentLength != 0 {
+		t.Errorf("expected an empty body to be sent unchunked, got %v %d", r.TransferEncoding, r.ContentLength)
+	}
+}
+
+// failingSeeker is a body whose position can't be read
+type failingSeeker struct{ *strings.Reader }
+
+func (failingSeeker) Seek(offset int64, whence int) (int64, error) {
+	return 0, fmt.Errorf("seek failed")
+}
+
+func TestDatabase_PutAttachmentSeekError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	client, err := CreateClientWithRetry("user", "pass", server.URL, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	database, err := client.Get("db")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	body := failingSeeker{strings.NewReader("abc")}
+	if _, err = database.PutAttachment("doc-1", "1-abc", "a.txt", "text/plain", body); err == nil {
+		t.Error("expected the seek error to be returned")
+	}
+}
+
+func TestDatabase_Attachment(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	data := bytes.Repeat([]byte("0123456789"), 100000)
+
+	meta, err := database.PutAttachment("doc-1", "", "data.bin", "application/octet-stream", bytes.NewReader(data))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if meta.ID != "doc-1" || !strings.HasPrefix(meta.Rev, "1-") {
+		t.Errorf("unexpected document meta-data %+v", meta)
+	}
+
+	info, err := database.AttachmentInfo("doc-1", "data.bin")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if info.ContentType != "application/octet-stream" || info.ContentLength != int64(len(data)) {
+		t.Errorf("unexpected attachment meta-data %+v", info)
+	}
+
+	att, err := database.GetAttachment("doc-1", "data.bin")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	downloaded, err := ioutil.ReadAll(att.Body)
+	att.Body.Close()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !bytes.Equal(data, downloaded) {
+		t.Errorf("unexpected attachment length %d", len(downloaded))
+	}
+	if err = att.VerifyDigest(); err != nil {
+		t.Error(err)
+	}
+
+	meta, err = database.DeleteAttachment("doc-1", meta.Rev, "data.bin")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !strings.HasPrefix(meta.Rev, "2-") {
+		t.Errorf("unexpected revision %s", meta.Rev)
+	}
+
+	_, err = database.AttachmentInfo("doc-1", "data.bin")
+	if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 {
+		t.Errorf("expected a 404 error, got %v", err)
+	}
+}
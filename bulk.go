@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -467,3 +468,95 @@ func getByFieldName(n interface{}, fieldName string) (string, bool) {
 		return "", false
 	}
 }
+
+// setByFieldName sets a string field of the struct n points to, returning
+// false if there is no such settable field.
+func setByFieldName(n interface{}, fieldName, value string) bool {
+	s := reflect.ValueOf(n)
+
+	if s.Kind() != reflect.Ptr || s.IsNil() {
+		return false
+	}
+	s = s.Elem()
+
+	if s.Kind() != reflect.Struct {
+		return false
+	}
+
+	f := s.FieldByName(fieldName)
+	if !f.IsValid() || !f.CanSet() || f.Kind() != reflect.String {
+		return false
+	}
+
+	f.SetString(value)
+	return true
+}
+
+// fieldNameByJSONTag returns the name of the struct field that n encodes to
+// JSON as name, such as "_id" for a field tagged `json:"_id"`.
+func fieldNameByJSONTag(n interface{}, name string) (string, bool) {
+	t := reflect.TypeOf(n)
+
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == nil || t.Kind() != reflect.Struct {
+		return "", false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if strings.Split(field.Tag.Get("json"), ",")[0] == name {
+			return field.Name, true
+		}
+	}
+
+	return "", false
+}
+
+// unknownFields returns the members of the JSON object data that don't map
+// to a field of the struct v, so that they can be written back unchanged.
+func unknownFields(data []byte, v interface{}) (map[string]json.RawMessage, error) {
+	members := map[string]json.RawMessage{}
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	t := reflect.TypeOf(v)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	for i := 0; i < t.NumField(); i++ {
+		delete(members, strings.Split(t.Field(i).Tag.Get("json"), ",")[0])
+	}
+
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return members, nil
+}
+
+// marshalWithUnknown encodes v, which must encode to a JSON object, adding
+// the members in unknown that v doesn't set itself.
+func marshalWithUnknown(v interface{}, unknown map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(unknown) == 0 {
+		return data, err
+	}
+
+	// Pointers, as Go < 1.8 encodes RawMessage values that aren't addressable,
+	// such as those held by a map, as base64 strings.
+	members := map[string]*json.RawMessage{}
+	if err = json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+	for key, value := range unknown {
+		if _, ok := members[key]; !ok {
+			value := value
+			members[key] = &value
+		}
+	}
+
+	return json.Marshal(members)
+}
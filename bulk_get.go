@@ -0,0 +1,194 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"sync"
+)
+
+var bulkGetChunkSize = 500 // documents requested per _bulk_get request
+
+// BulkGetRequest identifies a document to be fetched by BulkGet(). If Rev is
+// empty the winning revision is fetched.
+type BulkGetRequest struct {
+	ID        string   `json:"id"`
+	Rev       string   `json:"rev,omitempty"`
+	AttsSince []string `json:"atts_since,omitempty"`
+}
+
+// BulkGetResult is a document revision returned by BulkGet(). Error is set,
+// and Doc is nil, if the revision could not be fetched.
+type BulkGetResult struct {
+	ID    string
+	Rev   string
+	Doc   json.RawMessage
+	Error error
+}
+
+// bulkGetResponseRow is an entry of the results array returned by _bulk_get
+type bulkGetResponseRow struct {
+	ID   string `json:"id"`
+	Docs []struct {
+		OK    json.RawMessage `json:"ok"`
+		Error *struct {
+			ID     string `json:"id"`
+			Rev    string `json:"rev"`
+			Error  string `json:"error"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"docs"`
+}
+
+// BulkGet fetches many documents, or specific revisions of them, returning a
+// channel in which a BulkGetResult can be received for each. Large request
+// lists are split into chunks that are fetched concurrently, so results are
+// not received in the order they were requested.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/database.html#bulk-get
+func (d *Database) BulkGet(requests []BulkGetRequest, args *bulkGetQuery) (<-chan *BulkGetResult, error) {
+	params, err := args.GetQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*d.URL, "/_bulk_get", params)
+	if err != nil {
+		return nil, err
+	}
+
+	chunks := make(chan []BulkGetRequest, d.client.workerCount)
+	results := make(chan *BulkGetResult, 1000)
+
+	go func() {
+		defer close(chunks)
+		for start := 0; start < len(requests); start += bulkGetChunkSize {
+			end := start + bulkGetChunkSize
+			if end > len(requests) {
+				end = len(requests)
+			}
+			chunks <- requests[start:end]
+		}
+	}()
+
+	wg := &sync.WaitGroup{}
+	for i := 0; i < d.client.workerCount; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for chunk := range chunks {
+				d.bulkGetChunk(urlStr, chunk, results)
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results, nil
+}
+
+// bulkGetChunk fetches a chunk of documents. If the request fails an error
+// result is sent for each document of the chunk.
+func (d *Database) bulkGetChunk(urlStr string, chunk []BulkGetRequest, results chan<- *BulkGetResult) {
+	failFrom := func(i int, err error) {
+		for _, request := range chunk[i:] {
+			results <- &BulkGetResult{ID: request.ID, Rev: request.Rev, Error: err}
+		}
+	}
+
+	body, err := json.Marshal(map[string][]BulkGetRequest{"docs": chunk})
+	if err != nil {
+		failFrom(0, err)
+		return
+	}
+
+	job, err := d.client.request("POST", urlStr, bytes.NewReader(body))
+	defer job.Close()
+	if err != nil {
+		failFrom(0, err)
+		return
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		failFrom(0, err)
+		return
+	}
+
+	// Rows are returned in the order requested, so if the response is cut
+	// short only the documents not yet received are failed.
+	rows, err := decodeBulkGetResponse(job.response.Body, results)
+	if err != nil {
+		failFrom(rows, err)
+	}
+}
+
+// decodeBulkGetResponse streams the results of a _bulk_get response into
+// results, one row at a time. It returns the number of rows decoded.
+func decodeBulkGetResponse(body io.Reader, results chan<- *BulkGetResult) (int, error) {
+	decoder := json.NewDecoder(body)
+	rows := 0
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return rows, err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return rows, err
+		}
+
+		if token != "results" {
+			var skip json.RawMessage
+			if err = decoder.Decode(&skip); err != nil {
+				return rows, err
+			}
+			continue
+		}
+
+		if err = expectDelim(decoder, '['); err != nil {
+			return rows, err
+		}
+		for decoder.More() {
+			row := bulkGetResponseRow{}
+			if err = decoder.Decode(&row); err != nil {
+				return rows, err
+			}
+			for _, result := range row.results() {
+				results <- result
+			}
+			rows++
+		}
+		if err = expectDelim(decoder, ']'); err != nil {
+			return rows, err
+		}
+	}
+
+	return rows, nil
+}
+
+// results converts the documents of a response row to BulkGetResult types.
+func (r *bulkGetResponseRow) results() []*BulkGetResult {
+	results := make([]*BulkGetResult, 0, len(r.Docs))
+
+	for _, doc := range r.Docs {
+		result := &BulkGetResult{ID: r.ID}
+		if doc.Error != nil {
+			result.Rev = doc.Error.Rev
+			result.Error = &CouchError{Err: doc.Error.Error, Reason: doc.Error.Reason}
+		} else {
+			meta := &struct {
+				Rev string `json:"_rev"`
+			}{}
+			json.Unmarshal(doc.OK, meta)
+			result.Rev = meta.Rev
+			result.Doc = doc.OK
+		}
+		results = append(results, result)
+	}
+
+	return results
+}
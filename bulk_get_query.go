@@ -0,0 +1,82 @@
+package cloudant
+
+// QueryBuilder implementation for the BulkGet() API call.
+//
+// Example:
+// 	query := NewBulkGetQuery().
+//     Revs().
+//     Latest().
+//     Build()
+//
+//	results, err := db.BulkGet(requests, query)
+
+import (
+	"net/url"
+)
+
+// BulkGetQueryBuilder defines the available parameter-setting functions.
+type BulkGetQueryBuilder interface {
+	Attachments() BulkGetQueryBuilder
+	Latest() BulkGetQueryBuilder
+	Revs() BulkGetQueryBuilder
+	Build() *bulkGetQuery
+}
+
+type bulkGetQueryBuilder struct {
+	attachments bool
+	latest      bool
+	revs        bool
+}
+
+// bulkGetQuery holds the implemented API call parameters.
+type bulkGetQuery struct {
+	Attachments bool
+	Latest      bool
+	Revs        bool
+}
+
+// NewBulkGetQuery is the entry point.
+func NewBulkGetQuery() BulkGetQueryBuilder {
+	return &bulkGetQueryBuilder{}
+}
+
+func (b *bulkGetQueryBuilder) Attachments() BulkGetQueryBuilder {
+	b.attachments = true
+	return b
+}
+
+func (b *bulkGetQueryBuilder) Latest() BulkGetQueryBuilder {
+	b.latest = true
+	return b
+}
+
+func (b *bulkGetQueryBuilder) Revs() BulkGetQueryBuilder {
+	b.revs = true
+	return b
+}
+
+// GetQuery implements the QueryBuilder interface. It returns an
+// url.Values map with the non-default values set.
+func (bq *bulkGetQuery) GetQuery() (url.Values, error) {
+	vals := url.Values{}
+
+	if bq.Attachments {
+		vals.Set("attachments", "true")
+	}
+	if bq.Latest {
+		vals.Set("latest", "true")
+	}
+	if bq.Revs {
+		vals.Set("revs", "true")
+	}
+
+	return vals, nil
+}
+
+func (b *bulkGetQueryBuilder) Build() *bulkGetQuery {
+	return &bulkGetQuery{
+		Attachments: b.attachments,
+		Latest:      b.latest,
+		Revs:        b.revs,
+	}
+}
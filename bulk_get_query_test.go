@@ -0,0 +1,33 @@
+package cloudant
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestBulkGetQuery_Args(t *testing.T) {
+	// Attachments      bool
+	// Latest           bool
+	// Revs             bool
+
+	expectedQueryStrings := []string{
+		"attachments=true",
+		"latest=true",
+		"revs=true",
+	}
+
+	query := NewBulkGetQuery().
+		Attachments().
+		Latest().
+		Revs().
+		Build()
+
+	values, _ := query.GetQuery()
+	queryString := values.Encode()
+
+	for _, str := range expectedQueryStrings {
+		if !strings.Contains(queryString, str) {
+			t.Errorf("parameter encoding not found '%s'", str)
+		}
+	}
+}
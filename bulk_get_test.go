@@ -0,0 +1,98 @@
+package cloudant
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestBulkGet_Decode(t *testing.T) {
+	body := `{"results": [
+		{"id": "doc-1", "docs": [
+			{"ok": {"_id": "doc-1", "_rev": "1-abc", "foo": "bar"}},
+			{"error": {"id": "doc-1", "rev": "2-def", "error": "not_found", "reason": "missing"}}
+		]},
+		{"id": "doc-2", "docs": [
+			{"ok": {"_id": "doc-2", "_rev": "3-ghi"}}
+		]}
+	]}`
+
+	results := make(chan *BulkGetResult, 10)
+	rows, err := decodeBulkGetResponse(strings.NewReader(body), results)
+	close(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rows != 2 {
+		t.Errorf("unexpected number of rows %d", rows)
+	}
+
+	received := []*BulkGetResult{}
+	for result := range results {
+		received = append(received, result)
+	}
+	if len(received) != 3 {
+		t.Fatalf("unexpected number of results %d", len(received))
+	}
+
+	if received[0].ID != "doc-1" || received[0].Rev != "1-abc" || received[0].Error != nil ||
+		!strings.Contains(string(received[0].Doc), `"foo": "bar"`) {
+		t.Errorf("unexpected result %+v", received[0])
+	}
+	if dberr, ok := received[1].Error.(*CouchError); !ok || dberr.Err != "not_found" ||
+		received[1].Rev != "2-def" || received[1].Doc != nil {
+		t.Errorf("unexpected error result %+v", received[1])
+	}
+	if received[2].ID != "doc-2" || received[2].Rev != "3-ghi" {
+		t.Errorf("unexpected result %+v", received[2])
+	}
+}
+
+func TestDatabase_BulkGet(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	chunkSize := bulkGetChunkSize
+	bulkGetChunkSize = 3
+	defer func() { bulkGetChunkSize = chunkSize }()
+
+	requests := []BulkGetRequest{}
+	for i := 0; i < 10; i++ {
+		id := fmt.Sprintf("doc-%d", i)
+		if _, err = database.Set(map[string]interface{}{"_id": id, "i": i}); err != nil {
+			t.Fatalf("%s", err)
+		}
+		requests = append(requests, BulkGetRequest{ID: id})
+	}
+	requests = append(requests, BulkGetRequest{ID: "doc-missing"})
+
+	results, err := database.BulkGet(requests, NewBulkGetQuery().Revs().Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	found := 0
+	missing := 0
+	for result := range results {
+		if result.Error != nil {
+			missing++
+			if result.ID != "doc-missing" {
+				t.Errorf("unexpected error for %s: %s", result.ID, result.Error)
+			}
+			continue
+		}
+		found++
+		if !strings.HasPrefix(result.Rev, "1-") || !strings.Contains(string(result.Doc), `"_revisions"`) {
+			t.Errorf("unexpected result %s %s", result.ID, result.Doc)
+		}
+	}
+	if found != 10 || missing != 1 {
+		t.Errorf("unexpected results: %d found, %d missing", found, missing)
+	}
+}
@@ -0,0 +1,252 @@
+package cloudant
+
+import (
+	"bytes"
+	"container/list"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ResponseCache is an ETag-aware cache of GET responses, such as those of
+// Database.Get() and Database.View(). Responses carrying an ETag are stored
+// by URL, and repeat requests are sent with an If-None-Match header: if the
+// server replies 304 Not Modified the cached response is returned in its
+// place. Responses are always revalidated, so a cached response is never
+// returned once it is out of date.
+//
+// Example:
+//
+//	client.SetCache(NewResponseCache(10*1024*1024, time.Hour))
+type ResponseCache struct {
+	maxBytes int64
+	ttl      time.Duration
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     *list.List // most recently used first
+	bytes   int64
+	hits    int64
+	misses  int64
+}
+
+// CacheStats holds the counters of a ResponseCache
+type CacheStats struct {
+	Hits    int64 // Requests answered from the cache after a 304
+	Misses  int64 // Requests answered by the server
+	Entries int
+	Bytes   int64
+}
+
+type cacheEntry struct {
+	key    string
+	etag   string
+	header http.Header
+	body   []byte
+	stored time.Time
+}
+
+// NewResponseCache returns a cache holding up to maxBytes of response
+// bodies, each kept for at most ttl. A ttl of 0 keeps entries until they are
+// evicted to make room for others.
+func NewResponseCache(maxBytes int64, ttl time.Duration) *ResponseCache {
+	return &ResponseCache{
+		maxBytes: maxBytes,
+		ttl:      ttl,
+		entries:  map[string]*list.Element{},
+		lru:      list.New(),
+	}
+}
+
+// SetCache sets the response cache used by the client, or disables caching
+// if cache is nil. It must not be called while requests are in flight.
+func (c *CouchClient) SetCache(cache *ResponseCache) {
+	c.cache = cache
+}
+
+// Stats returns the cache counters.
+func (rc *ResponseCache) Stats() CacheStats {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	return CacheStats{
+		Hits:    rc.hits,
+		Misses:  rc.misses,
+		Entries: len(rc.entries),
+		Bytes:   rc.bytes,
+	}
+}
+
+// Invalidate removes the cached responses of all URLs starting with prefix,
+// for example the URL of a document to drop all cached versions of it.
+func (rc *ResponseCache) Invalidate(prefix string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	for key, element := range rc.entries {
+		if strings.HasPrefix(key, prefix) {
+			rc.remove(element)
+		}
+	}
+}
+
+// Clear removes all cached responses.
+func (rc *ResponseCache) Clear() {
+	rc.Invalidate("")
+}
+
+// cacheKey identifies a response by URL, and by the media type requested
+// since it changes the representation returned.
+func cacheKey(req *http.Request) string {
+	key := req.URL.String()
+	if accept := req.Header.Get("Accept"); accept != "" {
+		key += " " + accept
+	}
+	return key
+}
+
+// get returns the live entry for key, or nil.
+func (rc *ResponseCache) get(key string) *cacheEntry {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	element, ok := rc.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*cacheEntry)
+	if rc.ttl > 0 && time.Since(entry.stored) > rc.ttl {
+		rc.remove(element)
+		return nil
+	}
+
+	rc.lru.MoveToFront(element)
+
+	return entry
+}
+
+// put stores an entry, evicting the least recently used ones to make room.
+func (rc *ResponseCache) put(entry *cacheEntry) {
+	size := int64(len(entry.body))
+	if size > rc.maxBytes {
+		return
+	}
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if element, ok := rc.entries[entry.key]; ok {
+		rc.remove(element)
+	}
+
+	for rc.bytes+size > rc.maxBytes {
+		rc.remove(rc.lru.Back())
+	}
+
+	rc.entries[entry.key] = rc.lru.PushFront(entry)
+	rc.bytes += size
+}
+
+// remove drops an entry. The lock must be held.
+func (rc *ResponseCache) remove(element *list.Element) {
+	entry := rc.lru.Remove(element).(*cacheEntry)
+	delete(rc.entries, entry.key)
+	rc.bytes -= int64(len(entry.body))
+}
+
+// prepare adds an If-None-Match header to a GET request if a response to it
+// is cached, returning the cached entry.
+func (rc *ResponseCache) prepare(req *http.Request) *cacheEntry {
+	if req.Method != "GET" {
+		return nil
+	}
+
+	entry := rc.get(cacheKey(req))
+	if entry != nil {
+		req.Header.Set("If-None-Match", entry.etag)
+	}
+
+	return entry
+}
+
+// process serves a 304 response from the cached entry, or arranges for a
+// new response to be cached once its body has been read.
+func (rc *ResponseCache) process(req *http.Request, resp *http.Response, cached *cacheEntry) {
+	if req.Method != "GET" {
+		return
+	}
+
+	if resp.StatusCode == 304 && cached != nil {
+		rc.mu.Lock()
+		rc.hits++
+		rc.mu.Unlock()
+
+		resp.Body.Close()
+
+		resp.StatusCode = 200
+		resp.Status = "200 OK"
+		resp.Header = http.Header{}
+		for key, values := range cached.header {
+			resp.Header[key] = values
+		}
+		resp.Header.Set("Content-Length", strconv.Itoa(len(cached.body)))
+		resp.ContentLength = int64(len(cached.body))
+		resp.Body = ioutil.NopCloser(bytes.NewReader(cached.body))
+		return
+	}
+
+	rc.mu.Lock()
+	rc.misses++
+	rc.mu.Unlock()
+
+	etag := resp.Header.Get("ETag")
+	if resp.StatusCode != 200 || etag == "" {
+		return
+	}
+
+	resp.Body = &cachingReader{
+		ReadCloser: resp.Body,
+		cache:      rc,
+		entry: &cacheEntry{
+			key:    cacheKey(req),
+			etag:   etag,
+			header: resp.Header,
+		},
+	}
+}
+
+// cachingReader stores a response body in the cache once it has been read
+// to the end, unless it outgrows the cache.
+type cachingReader struct {
+	io.ReadCloser
+	cache    *ResponseCache
+	entry    *cacheEntry
+	buf      bytes.Buffer
+	overflow bool
+}
+
+func (r *cachingReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+
+	if !r.overflow {
+		r.buf.Write(p[:n])
+		if int64(r.buf.Len()) > r.cache.maxBytes {
+			r.overflow = true
+			r.buf = bytes.Buffer{}
+		}
+	}
+
+	if err == io.EOF && !r.overflow {
+		r.entry.body = r.buf.Bytes()
+		r.entry.stored = time.Now()
+		r.cache.put(r.entry)
+		r.overflow = true // store once only
+	}
+
+	return n, err
+}
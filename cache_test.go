@@ -0,0 +1,144 @@
+package cloudant
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func cacheTestResponse(req *http.Request, cache *ResponseCache, status int, body string) *http.Response {
+	cached := cache.prepare(req)
+
+	resp := &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       ioutil.NopCloser(strings.NewReader(body)),
+	}
+	resp.Header.Set("ETag", `"1-abc"`)
+	resp.Header.Set("Content-Type", "application/json")
+
+	cache.process(req, resp, cached)
+
+	return resp
+}
+
+func TestResponseCache_Revalidate(t *testing.T) {
+	cache := NewResponseCache(1024, time.Minute)
+
+	req, _ := http.NewRequest("GET", "http://localhost/db/doc", nil)
+	resp := cacheTestResponse(req, cache, 200, `{"_id":"doc"}`)
+	ioutil.ReadAll(resp.Body)
+
+	req, _ = http.NewRequest("GET", "http://localhost/db/doc", nil)
+	resp = cacheTestResponse(req, cache, 304, "")
+	if req.Header.Get("If-None-Match") != `"1-abc"` {
+		t.Errorf("unexpected If-None-Match '%s'", req.Header.Get("If-None-Match"))
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != 200 || string(body) != `{"_id":"doc"}` || resp.Header.Get("Content-Type") != "application/json" {
+		t.Errorf("unexpected cached response %d '%s'", resp.StatusCode, body)
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 || stats.Bytes != 13 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// The media type requested is part of the key
+	req, _ = http.NewRequest("GET", "http://localhost/db/doc", nil)
+	req.Header.Set("Accept", "multipart/related")
+	cache.prepare(req)
+	if req.Header.Get("If-None-Match") != "" {
+		t.Error("unexpected If-None-Match for a different media type")
+	}
+
+	cache.Invalidate("http://localhost/db/doc")
+	if stats = cache.Stats(); stats.Entries != 0 || stats.Bytes != 0 {
+		t.Errorf("unexpected stats after invalidation %+v", stats)
+	}
+}
+
+func TestResponseCache_Limits(t *testing.T) {
+	cache := NewResponseCache(10, 0)
+
+	for _, doc := range []string{"a", "b", "c"} {
+		req, _ := http.NewRequest("GET", "http://localhost/db/"+doc, nil)
+		resp := cacheTestResponse(req, cache, 200, "12345")
+		ioutil.ReadAll(resp.Body)
+	}
+
+	// The least recently used entry is evicted
+	if stats := cache.Stats(); stats.Entries != 2 || stats.Bytes != 10 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if cache.get("http://localhost/db/a") != nil || cache.get("http://localhost/db/c") == nil {
+		t.Error("expected the oldest entry to be evicted")
+	}
+
+	// Bodies larger than the cache aren't stored
+	req, _ := http.NewRequest("GET", "http://localhost/db/big", nil)
+	resp := cacheTestResponse(req, cache, 200, "12345678901")
+	ioutil.ReadAll(resp.Body)
+	if cache.get("http://localhost/db/big") != nil {
+		t.Error("unexpected entry for a large body")
+	}
+
+	cache = NewResponseCache(10, time.Millisecond)
+	req, _ = http.NewRequest("GET", "http://localhost/db/a", nil)
+	resp = cacheTestResponse(req, cache, 200, "12345")
+	ioutil.ReadAll(resp.Body)
+	time.Sleep(5 * time.Millisecond)
+	if cache.get("http://localhost/db/a") != nil {
+		t.Error("expected the entry to have expired")
+	}
+}
+
+func TestResponseCache_Database(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+		database.client.SetCache(nil)
+	}()
+
+	cache := NewResponseCache(1024*1024, time.Minute)
+	database.client.SetCache(cache)
+
+	meta, err := database.Set(map[string]interface{}{"_id": "config", "value": 1})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		doc := map[string]interface{}{}
+		if err = database.Get("config", NewGetQuery().Build(), &doc); err != nil {
+			t.Fatalf("%s", err)
+		}
+		if doc["_rev"] != meta.Rev {
+			t.Errorf("unexpected document %v", doc)
+		}
+	}
+
+	if stats := cache.Stats(); stats.Hits != 2 || stats.Entries != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+
+	// An updated document is fetched again
+	meta, err = database.Set(map[string]interface{}{"_id": "config", "_rev": meta.Rev, "value": 2})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	doc := map[string]interface{}{}
+	if err = database.Get("config", NewGetQuery().Build(), &doc); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if doc["_rev"] != meta.Rev {
+		t.Errorf("unexpected stale document %v", doc)
+	}
+}
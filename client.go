@@ -11,6 +11,7 @@ import (
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"os"
 	"path"
 	"time"
 )
@@ -39,6 +40,8 @@ type CouchClient struct {
 	workers       []*worker
 	workerChan    chan chan *Job
 	workerCount   int
+	cache         *ResponseCache
+	iam           *iamAuth
 }
 
 // QueryBuilder is used by functions implementing Cloudant API calls
@@ -66,6 +69,28 @@ func CreateClient(username, password, rootStrURL string, concurrency int) (*Couc
 func CreateClientWithRetry(username, password, rootStrURL string, concurrency, retryCountMax,
 	retryDelayMin, retryDelayMax int) (*CouchClient, error) {
 
+	couchClient, err := newClient(rootStrURL, concurrency, retryCountMax, retryDelayMin, retryDelayMax)
+	if err != nil {
+		return nil, err
+	}
+
+	couchClient.username = username
+	couchClient.password = password
+
+	startDispatcher(couchClient) // start workers
+
+	err = couchClient.LogIn() // create initial session
+	if err != nil {
+		return nil, err
+	}
+
+	return couchClient, nil
+}
+
+// newClient returns a client with no credentials and no workers started.
+func newClient(rootStrURL string, concurrency, retryCountMax, retryDelayMin,
+	retryDelayMax int) (*CouchClient, error) {
+
 	rand.Seed(time.Now().Unix()) // seed value for job retry start delays
 
 	cookieJar, _ := cookiejar.New(nil)
@@ -88,9 +113,7 @@ func CreateClientWithRetry(username, password, rootStrURL string, concurrency, r
 		return nil, err
 	}
 
-	couchClient := CouchClient{
-		username:      username,
-		password:      password,
+	couchClient := &CouchClient{
 		rootURL:       apiURL,
 		httpClient:    c,
 		jobQueue:      make(chan *Job, 100),
@@ -100,14 +123,7 @@ func CreateClientWithRetry(username, password, rootStrURL string, concurrency, r
 		workerCount:   concurrency,
 	}
 
-	startDispatcher(&couchClient) // start workers
-
-	err = couchClient.LogIn() // create initial session
-	if err != nil {
-		return nil, err
-	}
-
-	return &couchClient, nil
+	return couchClient, nil
 }
 
 // Delete deletes a specified database.
@@ -201,12 +217,29 @@ func (c *CouchClient) Get(databaseName string) (*Database, error) {
 // GetOrCreate returns a database.
 // If the database doesn't exist on the server then it will be created.
 func (c *CouchClient) GetOrCreate(databaseName string) (*Database, error) {
+	return c.getOrCreate(databaseName, nil)
+}
+
+// GetOrCreatePartitioned returns a database.
+// If the database doesn't exist on the server then it will be created as a
+// partitioned database. An existing database is returned as is, whether it is
+// partitioned or not.
+func (c *CouchClient) GetOrCreatePartitioned(databaseName string) (*Database, error) {
+	return c.getOrCreate(databaseName, url.Values{"partitioned": []string{"true"}})
+}
+
+func (c *CouchClient) getOrCreate(databaseName string, params url.Values) (*Database, error) {
 	database, err := c.Get(databaseName)
 	if err != nil {
 		return nil, err
 	}
 
-	job, err := c.request("PUT", database.URL.String(), nil)
+	urlStr, err := Endpoint(*database.URL, "", params)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := c.request("PUT", urlStr, nil)
 	defer job.Close()
 
 	if err != nil {
@@ -225,8 +258,13 @@ func (c *CouchClient) GetOrCreate(databaseName string) (*Database, error) {
 	return database, nil
 }
 
-// LogIn creates a session.
+// LogIn creates a session, or gets a new token for clients using IAM
+// authentication.
 func (c *CouchClient) LogIn() error {
+	if c.iam != nil {
+		return c.iam.refresh()
+	}
+
 	sessionURL := c.rootURL.String() + "/_session"
 
 	data := url.Values{}
@@ -267,16 +305,75 @@ func (c *CouchClient) LogOut() {
 }
 
 func (c *CouchClient) request(method, path string, body io.Reader) (job *Job, err error) {
+	return c.requestWithHeader(method, path, body, nil)
+}
+
+// requestWithHeader is like request, but sets additional request headers.
+// Headers given here override the default JSON Content-Type.
+func (c *CouchClient) requestWithHeader(method, path string, body io.Reader, header http.Header) (job *Job, err error) {
 	req, err := http.NewRequest(method, path, body)
 	if err != nil {
 		return nil, err
 	}
 
-	if req.Method == "POST" {
-		req.Header.Add("Content-Type", "application/json") // add Content-Type for POSTs
+	if req.Method == "POST" || (req.Method == "PUT" && body != nil) {
+		req.Header.Add("Content-Type", "application/json") // add Content-Type for POSTs and PUTs with a body
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	var cached *cacheEntry
+	if c.cache != nil {
+		cached = c.cache.prepare(req)
+	}
+
+	job = CreateJob(req)
+
+	c.Execute(job)
+	job.Wait()
+
+	if job.error != nil {
+		return job, job.error
+	}
+
+	if c.cache != nil {
+		c.cache.process(req, job.response, cached)
+	}
+
+	return job, nil
+}
+
+// requestStream is like requestWithHeader, but sends the body as it is read
+// rather than buffering it for retries. Failed requests are only retried if
+// the body implements io.Seeker. Its length is sent if it can be determined,
+// otherwise the body is sent chunked.
+func (c *CouchClient) requestStream(method, path string, body io.Reader, header http.Header) (job *Job, err error) {
+	req, err := http.NewRequest(method, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
 	}
 
 	job = CreateJob(req)
+	job.stream = body
+
+	if seeker, ok := body.(io.Seeker); ok {
+		job.streamPos, err = seeker.Seek(0, 1) // io.SeekCurrent, which needs Go 1.7
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	length, known := streamLength(body, job.streamPos)
+	if known && length == 0 {
+		job.stream = nil // nothing to stream, the request is sent without a body
+	}
+	req.ContentLength = length
 
 	c.Execute(job)
 	job.Wait()
@@ -288,6 +385,28 @@ func (c *CouchClient) request(method, path string, body io.Reader) (job *Job, er
 	return job, nil
 }
 
+// streamLength returns the number of bytes left in body, and whether it
+// could be determined.
+func streamLength(body io.Reader, pos int64) (int64, bool) {
+	switch b := body.(type) {
+	case interface {
+		Len() int
+	}:
+		return int64(b.Len()), true
+	case interface {
+		Stat() (os.FileInfo, error)
+	}:
+		info, err := b.Stat()
+		if err == nil && info.Mode().IsRegular() {
+			if info.Size() <= pos {
+				return 0, true
+			}
+			return info.Size() - pos, true
+		}
+	}
+	return 0, false
+}
+
 // Execute submits a job for execution.
 // The client must call `job.Wait()` before attempting access the response attribute.
 // Always call `job.Close()` to ensure the underlying connection is terminated.
@@ -309,4 +428,7 @@ func (c *CouchClient) Stop() {
 		worker.stop()
 	}
 
+	if c.iam != nil {
+		c.iam.stop()
+	}
 }
@@ -0,0 +1,253 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ConflictResolver merges the conflicting revisions of a document into the
+// body of a new winning revision. The current winning revision comes first,
+// followed by the conflicts. The _id, _rev and _conflicts attributes of the
+// returned document are ignored.
+type ConflictResolver func(revisions []map[string]interface{}) (map[string]interface{}, error)
+
+// openRevsRow is an entry of the response to an open_revs request
+type openRevsRow struct {
+	OK      map[string]interface{} `json:"ok"`
+	Missing string                 `json:"missing"`
+}
+
+// ResolveConflicts fetches the winning revision of a document and all of its
+// conflicting revisions, and merges them with resolver. The merged document
+// is written as the new winning revision, and the conflicting revisions are
+// deleted, in a single _bulk_docs request. If the document has no conflicts
+// nothing is written. Either way the meta-data of the winning revision is
+// returned.
+// See: https://console.bluemix.net/docs/services/Cloudant/guides/conflicts.html
+func (d *Database) ResolveConflicts(documentID string, resolver ConflictResolver) (*DocumentMeta, error) {
+	winner := map[string]interface{}{}
+	err := d.Get(documentID, NewGetQuery().Conflicts().Build(), &winner)
+	if err != nil {
+		return nil, err
+	}
+
+	winnerRev, _ := winner["_rev"].(string)
+	conflicts, _ := winner["_conflicts"].([]interface{})
+	if len(conflicts) == 0 {
+		return &DocumentMeta{ID: documentID, Rev: winnerRev}, nil
+	}
+	delete(winner, "_conflicts")
+
+	revs := make([]string, 0, len(conflicts))
+	for _, rev := range conflicts {
+		if s, ok := rev.(string); ok {
+			revs = append(revs, s)
+		}
+	}
+
+	leaves, err := d.openRevs(documentID, revs)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions := append([]map[string]interface{}{winner}, leaves...)
+
+	merged, err := resolver(revisions)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{}
+	for key, value := range merged {
+		doc[key] = value
+	}
+	doc["_id"] = documentID
+	doc["_rev"] = winnerRev
+	delete(doc, "_conflicts")
+
+	docs := []interface{}{doc}
+	for _, leaf := range leaves {
+		docs = append(docs, map[string]interface{}{
+			"_id":      documentID,
+			"_rev":     leaf["_rev"],
+			"_deleted": true,
+		})
+	}
+
+	result, err := UploadBulkDocs(&BulkDocsRequest{Docs: docs, NewEdits: true}, d)
+	if err != nil {
+		return nil, err
+	}
+	defer result.Close()
+
+	err = expectedReturnCodes(result, 201, 202)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := []BulkDocsResponse{}
+	if err = json.NewDecoder(result.response.Body).Decode(&responses); err != nil {
+		return nil, err
+	}
+	if len(responses) != len(docs) {
+		return nil, fmt.Errorf("unexpected response count: %d, expected: %d", len(responses), len(docs))
+	}
+	for _, response := range responses {
+		if response.Error != "" {
+			return nil, fmt.Errorf("failed to resolve conflict of %s %s: %s - %s",
+				response.ID, response.Rev, response.Error, response.Reason)
+		}
+	}
+
+	return &DocumentMeta{ID: responses[0].ID, Rev: responses[0].Rev}, nil
+}
+
+// openRevs fetches the given leaf revisions of a document, or all of its
+// leaf revisions if revs is nil. Revisions that can't be found are left out.
+func (d *Database) openRevs(documentID string, revs []string) ([]map[string]interface{}, error) {
+	params, err := NewGetQuery().OpenRevs(revs).Build().GetQuery()
+	if err != nil {
+		return nil, err
+	}
+	if revs == nil {
+		params.Set("open_revs", "all")
+	}
+
+	urlStr, err := Endpoint(*d.URL, documentID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "application/json")
+
+	job, err := d.client.requestWithHeader("GET", urlStr, nil, header)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []openRevsRow{}
+	if err = json.NewDecoder(job.response.Body).Decode(&rows); err != nil {
+		return nil, err
+	}
+
+	leaves := make([]map[string]interface{}, 0, len(rows))
+	for _, row := range rows {
+		if row.OK != nil {
+			leaves = append(leaves, row.OK)
+		}
+	}
+
+	return leaves, nil
+}
+
+// LatestTimestampResolver returns a ConflictResolver that picks the revision
+// with the latest value of the given field. Numeric values are compared as
+// numbers, and strings as RFC 3339 timestamps if they parse as such. The
+// current winner is kept if the field is missing from all revisions.
+func LatestTimestampResolver(field string) ConflictResolver {
+	return func(revisions []map[string]interface{}) (map[string]interface{}, error) {
+		if len(revisions) == 0 {
+			return nil, fmt.Errorf("no revisions to resolve")
+		}
+
+		latest := revisions[0]
+		for _, revision := range revisions[1:] {
+			if compareTimestamps(revision[field], latest[field]) > 0 {
+				latest = revision
+			}
+		}
+
+		return latest, nil
+	}
+}
+
+// compareTimestamps returns 1 if a is later than b, -1 if it is earlier and 0
+// if they are equal or can't be compared. Missing values are the earliest.
+func compareTimestamps(a, b interface{}) int {
+	if a == nil || b == nil {
+		switch {
+		case a != nil:
+			return 1
+		case b != nil:
+			return -1
+		}
+		return 0
+	}
+
+	switch aValue := a.(type) {
+	case float64:
+		if bValue, ok := b.(float64); ok {
+			switch {
+			case aValue > bValue:
+				return 1
+			case aValue < bValue:
+				return -1
+			}
+		}
+	case string:
+		bValue, ok := b.(string)
+		if !ok {
+			return 0
+		}
+		aTime, aErr := time.Parse(time.RFC3339Nano, aValue)
+		bTime, bErr := time.Parse(time.RFC3339Nano, bValue)
+		if aErr == nil && bErr == nil {
+			switch {
+			case aTime.After(bTime):
+				return 1
+			case aTime.Before(bTime):
+				return -1
+			}
+			return 0
+		}
+		switch {
+		case aValue > bValue:
+			return 1
+		case aValue < bValue:
+			return -1
+		}
+	}
+
+	return 0
+}
+
+// DeepMergeResolver is a ConflictResolver that merges the fields of all
+// revisions. Nested objects are merged recursively; where revisions hold
+// different values for a field the value of the earliest revision is kept,
+// so the current winner takes precedence.
+func DeepMergeResolver(revisions []map[string]interface{}) (map[string]interface{}, error) {
+	merged := map[string]interface{}{}
+	for i := len(revisions) - 1; i >= 0; i-- {
+		deepMerge(merged, revisions[i])
+	}
+
+	return merged, nil
+}
+
+// deepMerge copies the fields of src into dst, merging nested objects.
+func deepMerge(dst, src map[string]interface{}) {
+	for key, value := range src {
+		srcObject, srcIsObject := value.(map[string]interface{})
+		dstObject, dstIsObject := dst[key].(map[string]interface{})
+
+		if srcIsObject && dstIsObject {
+			deepMerge(dstObject, srcObject)
+			continue
+		}
+		if srcIsObject {
+			copied := map[string]interface{}{}
+			deepMerge(copied, srcObject)
+			value = copied
+		}
+		dst[key] = value
+	}
+}
@@ -0,0 +1,106 @@
+package cloudant
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestConflict_LatestTimestampResolver(t *testing.T) {
+	revisions := []map[string]interface{}{
+		{"_rev": "2-a", "updated": "2018-03-01T10:00:00Z"},
+		{"_rev": "2-b", "updated": "2018-03-01T11:00:00+02:00"},
+		{"_rev": "2-c", "updated": "2018-03-01T10:30:00Z"},
+		{"_rev": "2-d"},
+	}
+
+	latest, err := LatestTimestampResolver("updated")(revisions)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if latest["_rev"] != "2-c" {
+		t.Errorf("unexpected latest revision %v", latest["_rev"])
+	}
+
+	revisions = []map[string]interface{}{
+		{"_rev": "2-a", "ts": float64(100)},
+		{"_rev": "2-b", "ts": float64(200)},
+	}
+	latest, _ = LatestTimestampResolver("ts")(revisions)
+	if latest["_rev"] != "2-b" {
+		t.Errorf("unexpected latest revision %v", latest["_rev"])
+	}
+
+	latest, _ = LatestTimestampResolver("missing")(revisions)
+	if latest["_rev"] != "2-a" {
+		t.Errorf("expected the winner to be kept, got %v", latest["_rev"])
+	}
+}
+
+func TestConflict_DeepMergeResolver(t *testing.T) {
+	revisions := []map[string]interface{}{
+		{"name": "winner", "address": map[string]interface{}{"city": "Bristol"}},
+		{"name": "loser", "age": float64(42), "address": map[string]interface{}{"city": "Bath", "zip": "BA1"}},
+	}
+
+	merged, err := DeepMergeResolver(revisions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]interface{}{
+		"name":    "winner",
+		"age":     float64(42),
+		"address": map[string]interface{}{"city": "Bristol", "zip": "BA1"},
+	}
+	if !reflect.DeepEqual(expected, merged) {
+		t.Errorf("unexpected merge %v", merged)
+	}
+	if revisions[1]["address"].(map[string]interface{})["city"] != "Bath" {
+		t.Error("revisions must not be modified")
+	}
+}
+
+func TestDatabase_ResolveConflicts(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	// Create conflicting revisions by writing them without new edits. 1-bbb
+	// wins, so its "shared" value is kept by the merge.
+	docs := []interface{}{
+		map[string]interface{}{"_id": "doc-1", "_rev": "1-aaa", "a": "a", "shared": "a"},
+		map[string]interface{}{"_id": "doc-1", "_rev": "1-bbb", "b": "b", "shared": "b"},
+	}
+	result, err := UploadBulkDocs(&BulkDocsRequest{Docs: docs, NewEdits: false}, database)
+	result.Close()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	meta, err := database.ResolveConflicts("doc-1", DeepMergeResolver)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !strings.HasPrefix(meta.Rev, "2-") {
+		t.Errorf("unexpected revision %s", meta.Rev)
+	}
+
+	doc := map[string]interface{}{}
+	err = database.Get("doc-1", NewGetQuery().Conflicts().Build(), &doc)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if _, ok := doc["_conflicts"]; ok {
+		t.Errorf("unexpected conflicts %v", doc["_conflicts"])
+	}
+	if doc["a"] != "a" || doc["b"] != "b" || doc["shared"] != "b" {
+		t.Errorf("unexpected merged document %v", doc)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"bytes"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"strings"
 )
@@ -91,10 +92,44 @@ type Info struct {
 	DocCount         int    `json:"doc_count"`
 	DiskSize         int    `json:"disk_size"`
 	UpdateSeq        string `json:"update_seq"`
+	Props            struct {
+		Partitioned bool `json:"partitioned"`
+	} `json:"props"`
 }
 
 // All returns a channel in which AllRow types can be received.
 func (d *Database) All(args *allDocsQuery) (<-chan *AllRow, error) {
+	return d.allDocs("/_all_docs", args)
+}
+
+// allDocs streams the rows of _all_docs or any endpoint sharing its format.
+func (d *Database) allDocs(pathStr string, args *allDocsQuery) (<-chan *AllRow, error) {
+	lines, err := d.allDocsLines(pathStr, args)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan *AllRow, 1000)
+
+	go func(lines <-chan []byte, results chan<- *AllRow) {
+		defer close(results)
+
+		for line := range lines {
+			var result = new(AllRow)
+
+			err := json.Unmarshal(line, result)
+			if err == nil {
+				results <- result
+			}
+		}
+	}(lines, results)
+
+	return results, nil
+}
+
+// allDocsLines streams the rows of _all_docs or any endpoint sharing its
+// format as undecoded lines of JSON.
+func (d *Database) allDocsLines(pathStr string, args *allDocsQuery) (<-chan []byte, error) {
 	verb := "GET"
 	var body []byte
 	var err error
@@ -113,7 +148,7 @@ func (d *Database) All(args *allDocsQuery) (<-chan *AllRow, error) {
 		return nil, err
 	}
 
-	urlStr, err := Endpoint(*d.URL, "/_all_docs", params)
+	urlStr, err := Endpoint(*d.URL, pathStr, params)
 	if err != nil {
 		return nil, err
 	}
@@ -132,9 +167,9 @@ func (d *Database) All(args *allDocsQuery) (<-chan *AllRow, error) {
 		return nil, err
 	}
 
-	results := make(chan *AllRow, 1000)
+	lines := make(chan []byte, 1000)
 
-	go func(job *Job, results chan<- *AllRow) {
+	go func(job *Job, lines chan<- []byte) {
 		defer job.Close()
 
 		reader := bufio.NewReader(job.response.Body)
@@ -142,7 +177,7 @@ func (d *Database) All(args *allDocsQuery) (<-chan *AllRow, error) {
 		for {
 			line, err := reader.ReadBytes('\n')
 			if err != nil {
-				close(results)
+				close(lines)
 				return
 			}
 			lineStr := string(line)
@@ -150,17 +185,12 @@ func (d *Database) All(args *allDocsQuery) (<-chan *AllRow, error) {
 			lineStr = strings.TrimRight(lineStr, ",") // remove trailing comma
 
 			if len(lineStr) > 7 && lineStr[0:7] == "{\"id\":\"" {
-				var result = new(AllRow)
-
-				err := json.Unmarshal([]byte(lineStr), result)
-				if err == nil {
-					results <- result
-				}
+				lines <- []byte(lineStr)
 			}
 		}
-	}(job, results)
+	}(job, lines)
 
-	return results, nil
+	return lines, nil
 }
 
 // Bulk returns a new bulk document uploader.
@@ -171,6 +201,40 @@ func (d *Database) Bulk(batchSize int, batchMaxBytes int, flushSecs int) *Upload
 // Changes returns a channel in which Change types can be received.
 // See: https://console.bluemix.net/docs/services/Cloudant/api/database.html#get-changes
 func (d *Database) Changes(args *changesQuery) (<-chan *Change, error) {
+	lines, err := d.changesLines(args)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan *Change, 1000)
+
+	go func(lines <-chan []byte, changes chan<- *Change) {
+		defer close(changes)
+
+		for line := range lines {
+			var change = new(ChangeRow)
+
+			err := json.Unmarshal(line, change)
+			if err == nil && len(change.Changes) == 1 {
+				changes <- &Change{
+					ID:      change.ID,
+					Rev:     change.Changes[0].Rev,
+					Seq:     change.Seq,
+					Doc:     change.Doc,
+					Deleted: change.Deleted,
+				}
+			} else {
+				fmt.Println(err)
+			}
+		}
+	}(lines, changes)
+
+	return changes, nil
+}
+
+// changesLines streams the rows of the changes feed as undecoded lines of
+// JSON.
+func (d *Database) changesLines(args *changesQuery) (<-chan []byte, error) {
 	verb := "GET"
 	var body []byte
 	var err error
@@ -205,11 +269,11 @@ func (d *Database) Changes(args *changesQuery) (<-chan *Change, error) {
 		return nil, err
 	}
 
-	changes := make(chan *Change, 1000)
+	lines := make(chan []byte, 1000)
 
-	go func(job *Job, changes chan<- *Change) {
+	go func(job *Job, lines chan<- []byte) {
 		defer job.Close()
-		defer close(changes)
+		defer close(lines)
 
 		reader := bufio.NewReader(job.response.Body)
 
@@ -223,25 +287,12 @@ func (d *Database) Changes(args *changesQuery) (<-chan *Change, error) {
 			lineStr = strings.TrimRight(lineStr, ",") // remove trailing comma
 
 			if len(lineStr) > 7 && lineStr[0:7] == "{\"seq\":" {
-				var change = new(ChangeRow)
-
-				err := json.Unmarshal([]byte(lineStr), change)
-				if err == nil && len(change.Changes) == 1 {
-					changes <- &Change{
-						ID:      change.ID,
-						Rev:     change.Changes[0].Rev,
-						Seq:     change.Seq,
-						Doc:     change.Doc,
-						Deleted: change.Deleted,
-					}
-				} else {
-					fmt.Println(err)
-				}
+				lines <- []byte(lineStr)
 			}
 		}
-	}(job, changes)
+	}(job, lines)
 
-	return changes, nil
+	return lines, nil
 }
 
 // Info returns database information.
@@ -292,20 +343,35 @@ func (d *Database) Get(documentID string, args *getQuery, target interface{}) er
 
 // Delete a document with a specified revision.
 func (d *Database) Delete(documentID, rev string) error {
+	_, err := d.deleteDoc(documentID, rev)
+	return err
+}
+
+// deleteDoc deletes a document with a specified revision, returning the
+// revision of its tombstone.
+func (d *Database) deleteDoc(documentID, rev string) (*DocumentMeta, error) {
 	query := url.Values{}
 	query.Add("rev", rev)
 	urlStr, err := Endpoint(*d.URL, documentID, query)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
 	job, err := d.client.request("DELETE", urlStr, nil)
 	defer job.Close()
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	return expectedReturnCodes(job, 200)
+	err = expectedReturnCodes(job, 200, 202)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DocumentMeta{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+
+	return resp, err
 }
 
 // Set a document. The specified type may have a json attributes '_id' and '_rev'.
@@ -333,3 +399,76 @@ func (d *Database) Set(document interface{}) (*DocumentMeta, error) {
 
 	return resp, err
 }
+
+// Put writes a document to a given ID. To update an existing document its
+// current revision must be given, either in the document's _rev attribute or
+// with the Rev() query option. With the Batch() option the response holds no
+// revision.
+func (d *Database) Put(documentID string, document interface{}, args *putQuery) (*DocumentMeta, error) {
+	params, err := args.GetQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	jsonDocument, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*d.URL, documentID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("PUT", urlStr, bytes.NewReader(jsonDocument))
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 201, 202)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DocumentMeta{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+	if resp.Rev == "" {
+		resp.Rev = strings.Trim(job.response.Header.Get("ETag"), `"`)
+	}
+
+	return resp, err
+}
+
+// Copy copies a document on the server to destID without downloading it.
+// If the destination document already exists its current revision must be
+// given as destRev.
+func (d *Database) Copy(srcID, destID, destRev string) (*DocumentMeta, error) {
+	urlStr, err := Endpoint(*d.URL, srcID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	destination := escapePathSegment(destID)
+	if destRev != "" {
+		destination += "?rev=" + url.QueryEscape(destRev)
+	}
+	header := http.Header{}
+	header.Set("Destination", destination)
+
+	job, err := d.client.requestWithHeader("COPY", urlStr, nil, header)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 201, 202)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DocumentMeta{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+
+	return resp, err
+}
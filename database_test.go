@@ -3,6 +3,8 @@ package cloudant
 import (
 	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"strings"
 	"testing"
 	"time"
@@ -533,6 +535,126 @@ func TestDatabase_DeleteDoc(t *testing.T) {
 	}
 }
 
+func TestDatabase_Put(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	doc := map[string]interface{}{"foo": "bar"}
+
+	meta, err := database.Put("doc-put", doc, NewPutQuery().W(1).Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if meta.ID != "doc-put" || !strings.HasPrefix(meta.Rev, "1-") {
+		t.Errorf("unexpected document meta-data %+v", meta)
+	}
+
+	meta, err = database.Put("doc-put", doc, NewPutQuery().Rev(meta.Rev).Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !strings.HasPrefix(meta.Rev, "2-") {
+		t.Errorf("unexpected revision %s", meta.Rev)
+	}
+
+	_, err = database.Put("doc-put", doc, NewPutQuery().Build())
+	if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 409 {
+		t.Errorf("expected a conflict, got %v", err)
+	}
+
+	meta, err = database.Put("doc-batch", doc, NewPutQuery().Batch().Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if meta.ID != "doc-batch" {
+		t.Errorf("unexpected document meta-data %+v", meta)
+	}
+
+	replicated := map[string]interface{}{"_rev": "5-abc", "foo": "bar"}
+	meta, err = database.Put("doc-replicated", replicated, NewPutQuery().NewEdits(false).Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if meta.Rev != "5-abc" {
+		t.Errorf("unexpected revision %s", meta.Rev)
+	}
+}
+
+func TestDatabase_Copy(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	_, err = database.Set(map[string]interface{}{"_id": "template", "foo": "bar"})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	meta, err := database.Copy("template", "doc-1", "")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if meta.ID != "doc-1" || !strings.HasPrefix(meta.Rev, "1-") {
+		t.Errorf("unexpected document meta-data %+v", meta)
+	}
+
+	meta, err = database.Copy("template", "doc-1", meta.Rev)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !strings.HasPrefix(meta.Rev, "2-") {
+		t.Errorf("unexpected revision %s", meta.Rev)
+	}
+
+	doc := map[string]interface{}{}
+	database.Get("doc-1", NewGetQuery().Build(), &doc)
+	if doc["foo"] != "bar" {
+		t.Errorf("unexpected copied document %v", doc)
+	}
+}
+
+func TestDatabase_CopyDestination(t *testing.T) {
+	destinations := make(chan string, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_session" {
+			return
+		}
+		destinations <- r.Header.Get("Destination")
+		w.WriteHeader(201)
+		fmt.Fprint(w, `{"ok":true,"id":"a/b?c#d","rev":"2-abc"}`)
+	}))
+	defer server.Close()
+
+	client, err := CreateClientWithRetry("user", "pass", server.URL, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	database, err := client.Get("db")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if _, err = database.Copy("template", "a/b?c#d", "1-abc"); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if destination := <-destinations; destination != "a%2Fb%3Fc%23d?rev=1-abc" {
+		t.Errorf("unexpected Destination header %s", destination)
+	}
+}
+
 // TestDatabase_ChangesCouchDB16 checks that we can read old-style changes feeds
 // that uses a sequence ID which is an integer
 func TestDatabase_ChangesCouchDB16(t *testing.T) {
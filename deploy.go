@@ -0,0 +1,320 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"errors"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Constants defining the phases of a design document deployment
+const (
+	// DeployWrite is the write of the new definition to the temporary design document
+	DeployWrite = iota
+	// DeployBuild is reported periodically while the new view index builds
+	DeployBuild
+	// DeployPromote is the copy of the built design document over the live one
+	DeployPromote
+	// DeployCleanup is the removal of the temporary design document and old indexes
+	DeployCleanup
+	// DeployComplete means the new definition is live
+	DeployComplete
+)
+
+// ErrDeployCancelled is returned by DeployDesignDoc if it was cancelled
+var ErrDeployCancelled = errors.New("design document deployment cancelled")
+
+var deployPollInterval = 5 * time.Second // how often to check on index builds
+
+// DeployProgress is the message structure delivered to the progress callback
+// of DeployDesignDoc. ChangesDone, TotalChanges and Progress are only set
+// during the DeployBuild phase.
+type DeployProgress struct {
+	Phase        int
+	DesignDoc    string // The design document being worked on
+	ChangesDone  int
+	TotalChanges int
+	Progress     int // Percentage of the index built
+}
+
+// ActiveTask represents a task returned by _active_tasks
+type ActiveTask struct {
+	Type           string `json:"type"`
+	Database       string `json:"database"`
+	DesignDocument string `json:"design_document"`
+	Node           string `json:"node"`
+	Pid            string `json:"pid"`
+	Progress       int    `json:"progress"`
+	ChangesDone    int    `json:"changes_done"`
+	TotalChanges   int    `json:"total_changes"`
+	StartedOn      int64  `json:"started_on"`
+	UpdatedOn      int64  `json:"updated_on"`
+}
+
+// ViewInfo represents the meta-data of a design document's view index
+type ViewInfo struct {
+	Name      string `json:"name"`
+	ViewIndex struct {
+		CompactRunning bool   `json:"compact_running"`
+		UpdaterRunning bool   `json:"updater_running"`
+		WaitingClients int    `json:"waiting_clients"`
+		WaitingCommit  bool   `json:"waiting_commit"`
+		Signature      string `json:"signature"`
+		Language       string `json:"language"`
+	} `json:"view_index"`
+}
+
+// ActiveTasks returns the tasks currently running on the server.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/active_tasks.html
+func (c *CouchClient) ActiveTasks() ([]ActiveTask, error) {
+	urlStr, err := Endpoint(*c.rootURL, "/_active_tasks", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := c.request("GET", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := []ActiveTask{}
+	err = json.NewDecoder(job.response.Body).Decode(&tasks)
+
+	return tasks, err
+}
+
+// ViewInfo returns information about the view index of a design document.
+func (d *Database) ViewInfo(ddoc string) (*ViewInfo, error) {
+	urlStr, err := Endpoint(*d.URL, designDocID(ddoc)+"/_info", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("GET", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &ViewInfo{}
+	err = json.NewDecoder(job.response.Body).Decode(info)
+
+	return info, err
+}
+
+// ViewCleanup removes view index files that are no longer required by any
+// design document.
+func (d *Database) ViewCleanup() error {
+	urlStr, err := Endpoint(*d.URL, "/_view_cleanup", nil)
+	if err != nil {
+		return err
+	}
+
+	job, err := d.client.request("POST", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return err
+	}
+
+	return expectedReturnCodes(job, 200, 202)
+}
+
+// isTaskForDatabase matches the database of an active task, which is either
+// the database name (CouchDB 1.6) or the path of one of its shards.
+func isTaskForDatabase(taskDatabase, name string) bool {
+	if taskDatabase == name {
+		return true
+	}
+	return strings.HasPrefix(taskDatabase, "shards/") && strings.Contains(taskDatabase, "/"+name+".")
+}
+
+// DeployDesignDoc deploys a design document without serving queries from a
+// stale or partially built view index. The new definition is written to a
+// temporary "_design/{name}_new" document and its views are built in the
+// background. Once the index is up to date the temporary document is copied
+// over the live one, which then picks up the already built index, and the
+// temporary document and the old index files are removed.
+//
+// Progress is reported to progress, which may be nil. Closing cancel stops
+// the deployment and removes the temporary design document; the live design
+// document is left untouched unless it has already been replaced, in which
+// case the cleanup of old index files is skipped.
+func (d *Database) DeployDesignDoc(ddoc *DesignDocument, progress func(*DeployProgress), cancel <-chan struct{}) error {
+	report := func(p *DeployProgress) {
+		if progress != nil {
+			progress(p)
+		}
+	}
+
+	liveID := designDocID(ddoc.ID)
+
+	live, err := d.GetDesignDoc(liveID)
+	if err != nil {
+		if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 {
+			return err
+		}
+	}
+
+	if live != nil {
+		same, err := ddoc.Equal(live)
+		if err != nil {
+			return err
+		}
+		if same {
+			ddoc.ID = liveID
+			ddoc.Rev = live.Rev
+			report(&DeployProgress{Phase: DeployComplete, DesignDoc: liveID})
+			return nil
+		}
+	}
+
+	// Write the new definition under the temporary ID
+	tempDoc := *ddoc
+	tempDoc.ID = liveID + "_new"
+
+	report(&DeployProgress{Phase: DeployWrite, DesignDoc: tempDoc.ID})
+	if _, err = d.EnsureDesignDoc(&tempDoc); err != nil {
+		return err
+	}
+
+	if err = d.buildViews(&tempDoc, report, cancel); err != nil {
+		d.DeleteDesignDoc(tempDoc.ID, tempDoc.Rev) // best effort
+		return err
+	}
+	if isCancelled(cancel) {
+		d.DeleteDesignDoc(tempDoc.ID, tempDoc.Rev) // best effort
+		return ErrDeployCancelled
+	}
+
+	// Promote the temporary design document to the live ID
+	report(&DeployProgress{Phase: DeployPromote, DesignDoc: liveID})
+
+	liveRev := ""
+	if live != nil {
+		liveRev = live.Rev
+	}
+	meta, err := d.Copy(tempDoc.ID, liveID, liveRev)
+	if err != nil {
+		return err
+	}
+
+	ddoc.ID = liveID
+	ddoc.Rev = meta.Rev
+
+	if isCancelled(cancel) {
+		d.DeleteDesignDoc(tempDoc.ID, tempDoc.Rev) // best effort, old index files are left for the next cleanup
+		return ErrDeployCancelled
+	}
+
+	report(&DeployProgress{Phase: DeployCleanup, DesignDoc: tempDoc.ID})
+	if err = d.DeleteDesignDoc(tempDoc.ID, tempDoc.Rev); err != nil {
+		return err
+	}
+	if err = d.ViewCleanup(); err != nil {
+		return err
+	}
+
+	report(&DeployProgress{Phase: DeployComplete, DesignDoc: liveID})
+
+	return nil
+}
+
+// isCancelled reports whether cancel has been closed.
+func isCancelled(cancel <-chan struct{}) bool {
+	select {
+	case <-cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+// buildViews triggers a build of the view index of ddoc and waits for it to
+// complete, polling _active_tasks and the view info for progress.
+func (d *Database) buildViews(ddoc *DesignDocument, report func(*DeployProgress), cancel <-chan struct{}) error {
+	if len(ddoc.Views) == 0 {
+		return nil
+	}
+
+	// All views of a design document share an index, so querying any one of
+	// them builds all of them.
+	names := make([]string, 0, len(ddoc.Views))
+	for name := range ddoc.Views {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	view := names[0]
+
+	// Kick off the build without waiting for it
+	rows, err := d.View(ddoc.ID, view, NewViewQuery().Stale("update_after").Limit(1).Build())
+	if err != nil {
+		return err
+	}
+	for range rows {
+	}
+
+	for {
+		select {
+		case <-cancel:
+			return ErrDeployCancelled
+		case <-time.After(deployPollInterval):
+		}
+
+		tasks, err := d.client.ActiveTasks()
+		if err != nil {
+			return err
+		}
+
+		building := false
+		status := &DeployProgress{Phase: DeployBuild, DesignDoc: ddoc.ID}
+		for _, task := range tasks {
+			if task.Type == "indexer" && task.DesignDocument == ddoc.ID && isTaskForDatabase(task.Database, d.Name) {
+				building = true
+				status.ChangesDone += task.ChangesDone
+				status.TotalChanges += task.TotalChanges
+			}
+		}
+		if status.TotalChanges > 0 {
+			status.Progress = 100 * status.ChangesDone / status.TotalChanges
+		}
+
+		if !building {
+			info, err := d.ViewInfo(ddoc.ID)
+			if err != nil {
+				return err
+			}
+			building = info.ViewIndex.UpdaterRunning
+		}
+
+		if !building {
+			break
+		}
+
+		report(status)
+	}
+
+	// Make sure the index is complete: this only blocks if the build finished
+	// between polls and a few changes are still to be indexed.
+	rows, err = d.View(ddoc.ID, view, NewViewQuery().Limit(1).Build())
+	if err != nil {
+		return err
+	}
+	for range rows {
+	}
+
+	report(&DeployProgress{Phase: DeployBuild, DesignDoc: ddoc.ID, Progress: 100})
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package cloudant
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDeploy_IsTaskForDatabase(t *testing.T) {
+	if !isTaskForDatabase("mydb", "mydb") {
+		t.Error("expected CouchDB 1.6 database name to match")
+	}
+	if !isTaskForDatabase("shards/00000000-1fffffff/mydb.1518016523", "mydb") {
+		t.Error("expected shard name to match")
+	}
+	if isTaskForDatabase("shards/00000000-1fffffff/mydb2.1518016523", "mydb") {
+		t.Error("unexpected match of another database's shard")
+	}
+}
+
+func TestDatabase_DeployDesignDoc(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	makeDocuments(database, 1000)
+
+	pollInterval := deployPollInterval
+	deployPollInterval = 100 * time.Millisecond
+	defer func() { deployPollInterval = pollInterval }()
+
+	ddoc := NewDesignDocument("test")
+	ddoc.Views = map[string]ViewDefinition{
+		"bar": {Map: "function(doc) { emit(doc.bar); }", Reduce: "_count"},
+	}
+
+	phases := []int{}
+	progress := func(p *DeployProgress) {
+		if len(phases) == 0 || phases[len(phases)-1] != p.Phase {
+			phases = append(phases, p.Phase)
+		}
+	}
+
+	err = database.DeployDesignDoc(ddoc, progress, nil)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	expected := []int{DeployWrite, DeployBuild, DeployPromote, DeployCleanup, DeployComplete}
+	if fmt.Sprint(phases) != fmt.Sprint(expected) {
+		t.Errorf("unexpected deployment phases %v", phases)
+	}
+	if ddoc.ID != "_design/test" || !strings.HasPrefix(ddoc.Rev, "1-") {
+		t.Errorf("unexpected deployed design document %s %s", ddoc.ID, ddoc.Rev)
+	}
+
+	if _, err = database.GetDesignDoc("test_new"); err == nil {
+		t.Error("temporary design document not removed")
+	}
+
+	rows, err := database.View("test", "bar", NewViewQuery().Stale("ok").Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	row := <-rows
+	if count, _ := row.Count(); count != 1000 {
+		t.Errorf("expected a built index, got count %d", count)
+	}
+}
+
+func TestDatabase_DeployDesignDocCancel(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	pollInterval := deployPollInterval
+	deployPollInterval = 100 * time.Millisecond
+	defer func() { deployPollInterval = pollInterval }()
+
+	ddoc := NewDesignDocument("test")
+	ddoc.Views = map[string]ViewDefinition{
+		"bar": {Map: "function(doc) { emit(doc.bar); }"},
+	}
+
+	cancel := make(chan struct{})
+	close(cancel)
+
+	err = database.DeployDesignDoc(ddoc, nil, cancel)
+	if err != ErrDeployCancelled {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	if _, err = database.GetDesignDoc("test"); err == nil {
+		t.Error("cancelled design document deployed")
+	}
+	if _, err = database.GetDesignDoc("test_new"); err == nil {
+		t.Error("temporary design document not removed")
+	}
+}
+
+func TestDatabase_DeployDesignDocCancelBeforePromote(t *testing.T) {
+	requests := []string{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_session" {
+			return
+		}
+		requests = append(requests, r.Method+" "+r.URL.Path)
+		switch r.Method {
+		case "GET":
+			w.WriteHeader(404)
+			fmt.Fprint(w, `{"error":"not_found","reason":"missing"}`)
+		default:
+			w.WriteHeader(201)
+			fmt.Fprint(w, `{"ok":true,"id":"_design/test_new","rev":"1-abc"}`)
+		}
+	}))
+	defer server.Close()
+
+	client, err := CreateClientWithRetry("user", "pass", server.URL, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	database, err := client.Get("db")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	cancel := make(chan struct{})
+	close(cancel)
+
+	// no views to build, so cancellation is only noticed before promotion
+	err = database.DeployDesignDoc(NewDesignDocument("test"), nil, cancel)
+	if err != ErrDeployCancelled {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	for _, request := range requests {
+		if strings.HasPrefix(request, "COPY") {
+			t.Errorf("cancelled design document promoted: %v", requests)
+		}
+	}
+	if last := requests[len(requests)-1]; last != "DELETE /db/_design/test_new" {
+		t.Errorf("temporary design document not removed: %v", requests)
+	}
+}
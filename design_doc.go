@@ -0,0 +1,359 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+)
+
+// DesignDocument represents a design document holding the definitions of
+// views, search and geo indexes, and other server-side functions. Members
+// not listed here, such as autoupdate, are kept as they were read.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/design_documents.html
+type DesignDocument struct {
+	ID                string                           `json:"_id"`
+	Rev               string                           `json:"_rev,omitempty"`
+	Language          string                           `json:"language,omitempty"` // "query" for Cloudant Query indexes
+	Options           *DesignDocumentOptions           `json:"options,omitempty"`
+	Views             map[string]ViewDefinition        `json:"views,omitempty"`
+	Indexes           map[string]SearchIndexDefinition `json:"indexes,omitempty"`
+	StIndexes         map[string]GeoIndexDefinition    `json:"st_indexes,omitempty"`
+	Filters           map[string]string                `json:"filters,omitempty"`
+	Updates           map[string]string                `json:"updates,omitempty"`
+	Shows             map[string]string                `json:"shows,omitempty"`
+	Lists             map[string]string                `json:"lists,omitempty"`
+	ValidateDocUpdate string                           `json:"validate_doc_update,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// designDocumentJSON has the fields of DesignDocument without its methods
+type designDocumentJSON DesignDocument
+
+// DesignDocumentOptions holds the options that apply to all views and
+// indexes of a design document
+type DesignDocumentOptions struct {
+	Partitioned   *bool `json:"partitioned,omitempty"`
+	LocalSeq      bool  `json:"local_seq,omitempty"`
+	IncludeDesign bool  `json:"include_design,omitempty"`
+
+	unknown map[string]json.RawMessage
+}
+
+// designDocumentOptionsJSON has the fields of DesignDocumentOptions without
+// its methods
+type designDocumentOptionsJSON DesignDocumentOptions
+
+// ViewDefinition is a MapReduce view. For design documents created through
+// CreateIndex(), the view holds a Cloudant Query json index definition in
+// QueryIndex rather than a JavaScript function in Map. Other members, such as
+// the modules of the "lib" entry of the views, are kept as they were read.
+type ViewDefinition struct {
+	Map        string
+	Reduce     string
+	QueryIndex json.RawMessage
+	Options    map[string]interface{}
+
+	unknown map[string]json.RawMessage
+}
+
+type viewDefinitionJSON struct {
+	Map     json.RawMessage        `json:"map,omitempty"`
+	Reduce  string                 `json:"reduce,omitempty"`
+	Options map[string]interface{} `json:"options,omitempty"`
+}
+
+// SearchIndexDefinition is a Cloudant Search index. For design documents
+// created through CreateIndex(), the index holds a Cloudant Query text index
+// definition in QueryIndex rather than a JavaScript function in Index. Other
+// members are kept as they were read.
+type SearchIndexDefinition struct {
+	Index      string
+	QueryIndex json.RawMessage
+	Analyzer   interface{} // Either an analyzer name or a per-field analyzer object
+
+	unknown map[string]json.RawMessage
+}
+
+type searchIndexDefinitionJSON struct {
+	Index    json.RawMessage `json:"index"`
+	Analyzer interface{}     `json:"analyzer,omitempty"`
+}
+
+// GeoIndexDefinition is a Cloudant Geospatial index
+type GeoIndexDefinition struct {
+	Index string `json:"index"`
+}
+
+// NewDesignDocument returns an empty design document with the given name,
+// which may be given with or without the "_design/" prefix.
+func NewDesignDocument(name string) *DesignDocument {
+	return &DesignDocument{ID: designDocID(name)}
+}
+
+// functionOrObject encodes a JavaScript function, unless an object
+// definition has been given in its place.
+func functionOrObject(function string, object json.RawMessage) (json.RawMessage, error) {
+	if object != nil {
+		return object, nil
+	}
+	return json.Marshal(function)
+}
+
+// isObject tells apart object definitions from JavaScript function strings.
+func isObject(data json.RawMessage) bool {
+	data = bytes.TrimSpace(data)
+	return len(data) > 0 && data[0] == '{'
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (dd DesignDocument) MarshalJSON() ([]byte, error) {
+	return marshalWithUnknown(designDocumentJSON(dd), dd.unknown)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (dd *DesignDocument) UnmarshalJSON(data []byte) error {
+	ddoc := designDocumentJSON{}
+	if err := json.Unmarshal(data, &ddoc); err != nil {
+		return err
+	}
+
+	unknown, err := unknownFields(data, ddoc)
+	if err != nil {
+		return err
+	}
+
+	*dd = DesignDocument(ddoc)
+	dd.unknown = unknown
+
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (o DesignDocumentOptions) MarshalJSON() ([]byte, error) {
+	return marshalWithUnknown(designDocumentOptionsJSON(o), o.unknown)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (o *DesignDocumentOptions) UnmarshalJSON(data []byte) error {
+	options := designDocumentOptionsJSON{}
+	if err := json.Unmarshal(data, &options); err != nil {
+		return err
+	}
+
+	unknown, err := unknownFields(data, options)
+	if err != nil {
+		return err
+	}
+
+	*o = DesignDocumentOptions(options)
+	o.unknown = unknown
+
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (v ViewDefinition) MarshalJSON() ([]byte, error) {
+	var mapFunc json.RawMessage
+	if v.Map != "" || v.QueryIndex != nil {
+		var err error
+		if mapFunc, err = functionOrObject(v.Map, v.QueryIndex); err != nil {
+			return nil, err
+		}
+	}
+
+	// encoded through a pointer, so that Go < 1.8 writes Map as JSON
+	return marshalWithUnknown(&viewDefinitionJSON{
+		Map:     mapFunc,
+		Reduce:  v.Reduce,
+		Options: v.Options,
+	}, v.unknown)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (v *ViewDefinition) UnmarshalJSON(data []byte) error {
+	view := viewDefinitionJSON{}
+	if err := json.Unmarshal(data, &view); err != nil {
+		return err
+	}
+
+	unknown, err := unknownFields(data, view)
+	if err != nil {
+		return err
+	}
+
+	*v = ViewDefinition{Reduce: view.Reduce, Options: view.Options, unknown: unknown}
+	if isObject(view.Map) {
+		v.QueryIndex = view.Map
+		return nil
+	}
+	if len(view.Map) == 0 {
+		return nil
+	}
+	return json.Unmarshal(view.Map, &v.Map)
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s SearchIndexDefinition) MarshalJSON() ([]byte, error) {
+	index, err := functionOrObject(s.Index, s.QueryIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	// encoded through a pointer, so that Go < 1.8 writes Index as JSON
+	return marshalWithUnknown(&searchIndexDefinitionJSON{
+		Index:    index,
+		Analyzer: s.Analyzer,
+	}, s.unknown)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *SearchIndexDefinition) UnmarshalJSON(data []byte) error {
+	index := searchIndexDefinitionJSON{}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return err
+	}
+
+	unknown, err := unknownFields(data, index)
+	if err != nil {
+		return err
+	}
+
+	*s = SearchIndexDefinition{Analyzer: index.Analyzer, unknown: unknown}
+	if isObject(index.Index) {
+		s.QueryIndex = index.Index
+		return nil
+	}
+	if len(index.Index) == 0 {
+		return nil
+	}
+	return json.Unmarshal(index.Index, &s.Index)
+}
+
+// Equal reports whether two design documents hold the same definitions.
+// Their IDs and revisions are not compared.
+func (dd *DesignDocument) Equal(other *DesignDocument) (bool, error) {
+	a, err := canonicalDesignDoc(dd)
+	if err != nil {
+		return false, err
+	}
+	b, err := canonicalDesignDoc(other)
+	if err != nil {
+		return false, err
+	}
+
+	return reflect.DeepEqual(a, b), nil
+}
+
+// canonicalDesignDoc round-trips a design document through JSON, so that
+// equivalent definitions compare equal regardless of how they were built.
+func canonicalDesignDoc(ddoc *DesignDocument) (interface{}, error) {
+	stripped := *ddoc
+	stripped.ID = ""
+	stripped.Rev = ""
+
+	data, err := json.Marshal(stripped)
+	if err != nil {
+		return nil, err
+	}
+
+	var canonical interface{}
+	err = json.Unmarshal(data, &canonical)
+
+	return canonical, err
+}
+
+// GetDesignDoc fetches a design document, given its name with or without the
+// "_design/" prefix.
+func (d *Database) GetDesignDoc(name string) (*DesignDocument, error) {
+	ddoc := &DesignDocument{}
+	err := d.Get(designDocID(name), &getQuery{}, ddoc)
+	if err != nil {
+		return nil, err
+	}
+
+	return ddoc, nil
+}
+
+// PutDesignDoc creates or updates a design document. To update an existing
+// design document its current revision must be set. On success the new
+// revision is written back to ddoc.Rev.
+func (d *Database) PutDesignDoc(ddoc *DesignDocument) (*DocumentMeta, error) {
+	ddoc.ID = designDocID(ddoc.ID)
+
+	jsonDocument, err := json.Marshal(ddoc)
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*d.URL, ddoc.ID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("PUT", urlStr, bytes.NewReader(jsonDocument))
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 201, 202)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DocumentMeta{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+	if err == nil {
+		ddoc.Rev = resp.Rev
+	}
+
+	return resp, err
+}
+
+// DeleteDesignDoc deletes a design document with a specified revision.
+func (d *Database) DeleteDesignDoc(name, rev string) error {
+	return d.Delete(designDocID(name), rev)
+}
+
+// DesignDocs returns a channel in which the AllRow types of all design
+// documents can be received. It accepts the same query options as All().
+func (d *Database) DesignDocs(args *allDocsQuery) (<-chan *AllRow, error) {
+	return d.allDocs("/_design_docs", args)
+}
+
+// EnsureDesignDoc compares ddoc with the version held by the server and
+// writes it, reusing the server's current revision, only if the definitions
+// differ. Skipping unchanged design documents avoids needless index rebuilds
+// on deployment. It returns true if the design document was written. Either
+// way ddoc.Rev is set to the server's current revision.
+func (d *Database) EnsureDesignDoc(ddoc *DesignDocument) (bool, error) {
+	ddoc.ID = designDocID(ddoc.ID)
+	ddoc.Rev = ""
+
+	current, err := d.GetDesignDoc(ddoc.ID)
+	if err != nil {
+		if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 {
+			return false, err
+		}
+	}
+
+	if current != nil {
+		ddoc.Rev = current.Rev
+
+		same, err := ddoc.Equal(current)
+		if err != nil {
+			return false, err
+		}
+		if same {
+			return false, nil
+		}
+	}
+
+	_, err = d.PutDesignDoc(ddoc)
+	if err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
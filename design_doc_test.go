@@ -0,0 +1,194 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDesignDocument_RoundTrip(t *testing.T) {
+	data := []byte(`{
+		"_id": "_design/mango",
+		"_rev": "1-abc",
+		"language": "query",
+		"views": {
+			"foo-json": {
+				"map": {"fields": {"foo": "asc"}, "partial_filter_selector": {}},
+				"reduce": "_count",
+				"options": {"def": {"fields": ["foo"]}}
+			}
+		},
+		"indexes": {
+			"foo-text": {
+				"index": {"default_analyzer": "keyword", "fields": [{"foo": "string"}]},
+				"analyzer": {"name": "perfield", "default": "keyword"}
+			}
+		},
+		"options": {"partitioned": false}
+	}`)
+
+	ddoc := &DesignDocument{}
+	if err := json.Unmarshal(data, ddoc); err != nil {
+		t.Fatal(err)
+	}
+	if ddoc.Views["foo-json"].QueryIndex == nil || ddoc.Views["foo-json"].Map != "" {
+		t.Errorf("unexpected view %+v", ddoc.Views["foo-json"])
+	}
+	if ddoc.Indexes["foo-text"].QueryIndex == nil {
+		t.Errorf("unexpected search index %+v", ddoc.Indexes["foo-text"])
+	}
+	if ddoc.Options == nil || ddoc.Options.Partitioned == nil || *ddoc.Options.Partitioned {
+		t.Errorf("unexpected options %+v", ddoc.Options)
+	}
+
+	out, err := json.Marshal(ddoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(out), `"map":{"fields":{"foo":"asc"},"partial_filter_selector":{}}`) {
+		t.Errorf("query index not preserved '%s'", out)
+	}
+
+	copied := &DesignDocument{}
+	json.Unmarshal(out, copied)
+	copied.Rev = "2-def"
+	if same, err := ddoc.Equal(copied); err != nil || !same {
+		t.Errorf("expected round-tripped design document to be equal (%v)", err)
+	}
+}
+
+func TestDesignDocument_UnknownMembers(t *testing.T) {
+	data := []byte(`{
+		"_id": "_design/app",
+		"autoupdate": false,
+		"views": {
+			"lib": {"utils": "exports.key = function(doc) { return doc.key; };"},
+			"by-key": {"map": "function(doc) { emit(require('views/lib/utils').key(doc)); }"}
+		},
+		"indexes": {
+			"by-name": {
+				"index": "function(doc) { index('name', doc.name); }",
+				"analyzer": {"name": "perfield", "default": "standard", "fields": {"name": "keyword"}},
+				"description": "names"
+			}
+		},
+		"options": {"partitioned": true, "other": 1}
+	}`)
+
+	ddoc := &DesignDocument{}
+	if err := json.Unmarshal(data, ddoc); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := json.Marshal(ddoc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, member := range []string{
+		`"autoupdate":false`,
+		`"lib":{"utils":"exports.key = function(doc) { return doc.key; };"}`,
+		`"other":1`,
+		`"description":"names"`,
+		`"fields":{"name":"keyword"}`,
+	} {
+		if !strings.Contains(string(out), member) {
+			t.Errorf("member %s not preserved '%s'", member, out)
+		}
+	}
+
+	changed := &DesignDocument{}
+	json.Unmarshal([]byte(strings.Replace(string(data), `"autoupdate": false`, `"autoupdate": true`, 1)), changed)
+	if same, _ := ddoc.Equal(changed); same {
+		t.Error("expected design documents with different autoupdate to differ")
+	}
+}
+
+func TestDesignDocument_Equal(t *testing.T) {
+	ddoc1 := NewDesignDocument("test")
+	ddoc1.Views = map[string]ViewDefinition{
+		"bar": {Map: "function(doc) { emit(doc.bar); }", Reduce: "_count"},
+	}
+
+	ddoc2 := NewDesignDocument("_design/test")
+	ddoc2.Rev = "1-abc"
+	ddoc2.Views = map[string]ViewDefinition{
+		"bar": {Map: "function(doc) { emit(doc.bar); }", Reduce: "_count"},
+	}
+
+	if ddoc1.ID != ddoc2.ID {
+		t.Errorf("unexpected design document IDs %s, %s", ddoc1.ID, ddoc2.ID)
+	}
+	if same, _ := ddoc1.Equal(ddoc2); !same {
+		t.Error("expected design documents to be equal")
+	}
+
+	ddoc2.Views["bar"] = ViewDefinition{Map: "function(doc) { emit(doc.bar); }", Reduce: "_sum"}
+	if same, _ := ddoc1.Equal(ddoc2); same {
+		t.Error("expected design documents to differ")
+	}
+}
+
+func TestDatabase_EnsureDesignDoc(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	ddoc := NewDesignDocument("test")
+	ddoc.Views = map[string]ViewDefinition{
+		"bar": {Map: "function(doc) { emit(doc.bar); }", Reduce: "_count"},
+	}
+
+	written, err := database.EnsureDesignDoc(ddoc)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !written || !strings.HasPrefix(ddoc.Rev, "1-") {
+		t.Errorf("expected design document to be created, got rev %s", ddoc.Rev)
+	}
+
+	written, err = database.EnsureDesignDoc(ddoc)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if written || !strings.HasPrefix(ddoc.Rev, "1-") {
+		t.Errorf("expected unchanged design document to be skipped, got rev %s", ddoc.Rev)
+	}
+
+	ddoc.Views["baz"] = ViewDefinition{Map: "function(doc) { emit(doc.foo); }"}
+	written, err = database.EnsureDesignDoc(ddoc)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !written || !strings.HasPrefix(ddoc.Rev, "2-") {
+		t.Errorf("expected design document to be updated, got rev %s", ddoc.Rev)
+	}
+
+	rows, err := database.DesignDocs(NewAllDocsQuery().Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	i := 0
+	for row := range rows {
+		i++
+		if row.ID != "_design/test" {
+			t.Errorf("unexpected design document %s", row.ID)
+		}
+	}
+	if 1 != i {
+		t.Errorf("unexpected number of design documents %d", i)
+	}
+
+	fetched, err := database.GetDesignDoc("test")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if err = database.DeleteDesignDoc("test", fetched.Rev); err != nil {
+		t.Errorf("failed to delete design document: %s", err)
+	}
+}
@@ -0,0 +1,106 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// FunctionResponse is the raw response of an update handler, show or list
+// function.
+type FunctionResponse struct {
+	StatusCode  int
+	ContentType string
+	Header      http.Header
+	Body        []byte
+	DocID       string // X-Couch-Id, set by update handlers that save a document
+	NewRev      string // X-Couch-Update-NewRev, set by update handlers that save a document
+}
+
+// UpdateHandler invokes an update handler of a design document. If
+// documentID is empty the handler is called with a null document through a
+// POST, otherwise the document is loaded and the handler called through a
+// PUT. The body is sent as is, with the given Content-Type, and may be nil.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/design_documents.html#update-handlers
+func (d *Database) UpdateHandler(ddoc, handler, documentID string, params url.Values, contentType string,
+	body io.Reader) (*FunctionResponse, error) {
+
+	pathStr := designDocID(ddoc) + "/_update/" + handler
+	method := "POST"
+	if documentID != "" {
+		pathStr += "/" + documentID
+		method = "PUT"
+	}
+
+	return d.callFunction(method, pathStr, params, contentType, body)
+}
+
+// Show invokes a show function of a design document on a document, or on a
+// null document if documentID is empty.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/design_documents.html#show-functions
+func (d *Database) Show(ddoc, function, documentID string, params url.Values) (*FunctionResponse, error) {
+	pathStr := designDocID(ddoc) + "/_show/" + function
+	if documentID != "" {
+		pathStr += "/" + documentID
+	}
+
+	return d.callFunction("GET", pathStr, params, "", nil)
+}
+
+// List invokes a list function of a design document on the rows of a view.
+// The view may belong to another design document, given as "ddoc/view".
+// See: https://console.bluemix.net/docs/services/Cloudant/api/design_documents.html#list-functions
+func (d *Database) List(ddoc, function, view string, params url.Values) (*FunctionResponse, error) {
+	pathStr := designDocID(ddoc) + "/_list/" + function + "/" + view
+
+	return d.callFunction("GET", pathStr, params, "", nil)
+}
+
+// callFunction makes a request to a design document function and returns
+// its raw response. Responses with an error status are returned as a
+// CouchError.
+func (d *Database) callFunction(method, pathStr string, params url.Values, contentType string,
+	body io.Reader) (*FunctionResponse, error) {
+
+	urlStr, err := Endpoint(*d.URL, pathStr, params)
+	if err != nil {
+		return nil, err
+	}
+
+	var header http.Header
+	if contentType != "" {
+		header = http.Header{"Content-Type": []string{contentType}}
+	}
+
+	job, err := d.client.requestWithHeader(method, urlStr, body, header)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := ioutil.ReadAll(job.response.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	if job.response.StatusCode >= 400 {
+		dbError := &CouchError{}
+		if json.Unmarshal(data, dbError) != nil || dbError.Err == "" {
+			dbError.Err = strings.TrimSpace(string(data))
+		}
+		dbError.StatusCode = job.response.StatusCode
+		return nil, dbError
+	}
+
+	return &FunctionResponse{
+		StatusCode:  job.response.StatusCode,
+		ContentType: job.response.Header.Get("Content-Type"),
+		Header:      job.response.Header,
+		Body:        data,
+		DocID:       job.response.Header.Get("X-Couch-Id"),
+		NewRev:      job.response.Header.Get("X-Couch-Update-NewRev"),
+	}, nil
+}
@@ -0,0 +1,77 @@
+package cloudant
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func TestDatabase_DesignFunctions(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	ddoc := NewDesignDocument("funcs")
+	ddoc.Updates = map[string]string{
+		"stamp": `function(doc, req) {
+			if (!doc) { doc = {_id: req.id}; }
+			doc.stamp = req.query.stamp;
+			doc.body = req.body;
+			return [doc, "stamped " + doc._id];
+		}`,
+	}
+	ddoc.Shows = map[string]string{
+		"stamp": `function(doc, req) {
+			return {body: "<p>" + doc.stamp + "</p>", headers: {"Content-Type": "text/html"}};
+		}`,
+	}
+	ddoc.Views = map[string]ViewDefinition{
+		"stamps": {Map: "function(doc) { if (doc.stamp) { emit(doc.stamp); } }"},
+	}
+	ddoc.Lists = map[string]string{
+		"csv": `function(head, req) {
+			start({headers: {"Content-Type": "text/csv"}});
+			var row;
+			while (row = getRow()) { send(row.key + "\n"); }
+		}`,
+	}
+	if _, err = database.PutDesignDoc(ddoc); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	resp, err := database.UpdateHandler("funcs", "stamp", "doc-1", url.Values{"stamp": []string{"one"}},
+		"text/plain", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if string(resp.Body) != "stamped doc-1" || !strings.HasPrefix(resp.NewRev, "1-") {
+		t.Errorf("unexpected update response %+v", resp)
+	}
+
+	resp, err = database.Show("funcs", "stamp", "doc-1", nil)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if string(resp.Body) != "<p>one</p>" || !strings.HasPrefix(resp.ContentType, "text/html") {
+		t.Errorf("unexpected show response %+v", resp)
+	}
+
+	resp, err = database.List("funcs", "csv", "stamps", nil)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if string(resp.Body) != "one\n" || !strings.HasPrefix(resp.ContentType, "text/csv") {
+		t.Errorf("unexpected list response %+v", resp)
+	}
+
+	_, err = database.Show("funcs", "missing", "doc-1", nil)
+	if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 {
+		t.Errorf("expected a 404 error, got %v", err)
+	}
+}
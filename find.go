@@ -0,0 +1,217 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+var defaultFindLimit = 25 // server-side default page size for _find
+
+// ExecutionStats is returned by _find if the query asked for execution_stats
+type ExecutionStats struct {
+	TotalKeysExamined       int     `json:"total_keys_examined"`
+	TotalDocsExamined       int     `json:"total_docs_examined"`
+	TotalQuorumDocsExamined int     `json:"total_quorum_docs_examined"`
+	ResultsReturned         int     `json:"results_returned"`
+	ExecutionTimeMs         float64 `json:"execution_time_ms"`
+}
+
+// FindResponse holds the streamed result of a _find request. Documents are
+// received from the Docs channel. The remaining fields are only populated
+// once the Docs channel has been closed.
+type FindResponse struct {
+	Docs           <-chan json.RawMessage
+	Bookmark       string
+	Warning        string
+	ExecutionStats *ExecutionStats
+	Err            error // Set if the response stream could not be decoded
+}
+
+// Find queries the database with a Cloudant Query selector.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/cloudant_query.html#finding-documents-by-using-an-index
+func (d *Database) Find(args *findQuery) (*FindResponse, error) {
+	urlStr, err := Endpoint(*d.URL, "/_find", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.find(urlStr, args)
+}
+
+func (d *Database) find(urlStr string, args *findQuery) (*FindResponse, error) {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("POST", urlStr, bytes.NewReader(body))
+	if err != nil {
+		if job != nil {
+			job.Close() // close the body reader to avoid leakage
+		}
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		job.Close() // close the body reader to avoid leakage
+		return nil, err
+	}
+
+	docs := make(chan json.RawMessage, 1000)
+	response := &FindResponse{Docs: docs}
+
+	go func(job *Job) {
+		defer job.Close()
+		defer close(docs)
+
+		response.Err = decodeFindResponse(job.response.Body, docs, response)
+	}(job)
+
+	return response, nil
+}
+
+// decodeFindResponse reads a _find response body, sending each document to
+// docs as soon as it has been decoded, and recording the trailing fields on
+// response.
+func decodeFindResponse(body io.Reader, docs chan<- json.RawMessage, response *FindResponse) error {
+	decoder := json.NewDecoder(body)
+
+	if err := expectDelim(decoder, '{'); err != nil {
+		return err
+	}
+
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+
+		switch token {
+		case "docs":
+			if err := expectDelim(decoder, '['); err != nil {
+				return err
+			}
+			for decoder.More() {
+				var doc json.RawMessage
+				if err := decoder.Decode(&doc); err != nil {
+					return err
+				}
+				docs <- doc
+			}
+			if err := expectDelim(decoder, ']'); err != nil {
+				return err
+			}
+		case "bookmark":
+			err = decoder.Decode(&response.Bookmark)
+		case "warning":
+			err = decoder.Decode(&response.Warning)
+		case "execution_stats":
+			response.ExecutionStats = &ExecutionStats{}
+			err = decoder.Decode(response.ExecutionStats)
+		default:
+			var ignored json.RawMessage
+			err = decoder.Decode(&ignored)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return expectDelim(decoder, '}')
+}
+
+func expectDelim(decoder *json.Decoder, delim json.Delim) error {
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("unexpected JSON token %v, expected %v", token, delim)
+	}
+	return nil
+}
+
+// FindPager follows _find bookmarks, fetching one page of results at a time
+// until the result set is exhausted.
+//
+// Example:
+//
+//	pager := NewFindPager(db, query)
+//	for pager.Next() {
+//		for _, doc := range pager.Docs() {
+//			...
+//		}
+//	}
+//	if err := pager.Err(); err != nil {
+//		...
+//	}
+type FindPager struct {
+	db    *Database
+	query findQuery
+	docs  []json.RawMessage
+	err   error
+	done  bool
+}
+
+// NewFindPager creates a FindPager for query on database. The query's limit
+// sets the page size.
+func NewFindPager(database *Database, query *findQuery) *FindPager {
+	return &FindPager{
+		db:    database,
+		query: *query,
+	}
+}
+
+// Next fetches the next page of results. It returns false once there are no
+// more results or an error has occurred.
+func (p *FindPager) Next() bool {
+	if p.done {
+		return false
+	}
+
+	p.docs = nil
+
+	response, err := p.db.Find(&p.query)
+	if err != nil {
+		p.err = err
+		p.done = true
+		return false
+	}
+
+	for doc := range response.Docs {
+		p.docs = append(p.docs, doc)
+	}
+	if response.Err != nil {
+		p.err = response.Err
+		p.done = true
+		return false
+	}
+
+	limit := p.query.Limit
+	if limit <= 0 {
+		limit = defaultFindLimit
+	}
+
+	// A short page, or a bookmark that doesn't move on, means we've reached
+	// the end of the result set.
+	if len(p.docs) < limit || response.Bookmark == "" || response.Bookmark == p.query.Bookmark {
+		p.done = true
+	}
+	p.query.Bookmark = response.Bookmark
+	p.query.Skip = 0 // only skip on the first page
+
+	return len(p.docs) > 0
+}
+
+// Docs returns the documents of the current page.
+func (p *FindPager) Docs() []json.RawMessage { return p.docs }
+
+// Bookmark returns the bookmark of the current page. It can be used to resume
+// paging at a later time with FindQueryBuilder.Bookmark().
+func (p *FindPager) Bookmark() string { return p.query.Bookmark }
+
+// Err returns the first error encountered while paging, if any.
+func (p *FindPager) Err() error { return p.err }
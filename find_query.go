@@ -0,0 +1,206 @@
+package cloudant
+
+// QueryBuilder implementation for the Find() API call. Unlike the other query
+// builders the parameters are sent as a JSON request body rather than in the
+// query string.
+//
+// Example:
+// 	query := NewFindQuery().
+//     Selector(And(Eq("type", "user"), Gt("age", 21))).
+//     Fields([]string{"_id", "name"}).
+//     Sort("age", "desc").
+//     Limit(50).
+//     Build()
+//
+//	result, err := db.Find(query)
+
+// Selector is a Cloudant Query selector. Selectors can be created with the
+// operator functions below and are combined with And(), Or() and friends.
+// Any map that follows the Cloudant Query syntax is also a valid Selector.
+type Selector map[string]interface{}
+
+func fieldSelector(field, operator string, value interface{}) Selector {
+	return Selector{field: map[string]interface{}{operator: value}}
+}
+
+func combinedSelector(operator string, selectors []Selector) Selector {
+	return Selector{operator: selectors}
+}
+
+// Eq matches documents where field is equal to value.
+func Eq(field string, value interface{}) Selector { return fieldSelector(field, "$eq", value) }
+
+// Ne matches documents where field is not equal to value.
+func Ne(field string, value interface{}) Selector { return fieldSelector(field, "$ne", value) }
+
+// Gt matches documents where field is greater than value.
+func Gt(field string, value interface{}) Selector { return fieldSelector(field, "$gt", value) }
+
+// Gte matches documents where field is greater than or equal to value.
+func Gte(field string, value interface{}) Selector { return fieldSelector(field, "$gte", value) }
+
+// Lt matches documents where field is less than value.
+func Lt(field string, value interface{}) Selector { return fieldSelector(field, "$lt", value) }
+
+// Lte matches documents where field is less than or equal to value.
+func Lte(field string, value interface{}) Selector { return fieldSelector(field, "$lte", value) }
+
+// In matches documents where field is equal to any of values.
+func In(field string, values ...interface{}) Selector { return fieldSelector(field, "$in", values) }
+
+// Nin matches documents where field is equal to none of values.
+func Nin(field string, values ...interface{}) Selector { return fieldSelector(field, "$nin", values) }
+
+// Exists matches documents where the presence of field is equal to exists.
+func Exists(field string, exists bool) Selector { return fieldSelector(field, "$exists", exists) }
+
+// Regex matches documents where field is a string matching the PCRE pattern.
+func Regex(field, pattern string) Selector { return fieldSelector(field, "$regex", pattern) }
+
+// ElemMatch matches documents where field is an array containing at least one
+// element matching selector.
+func ElemMatch(field string, selector Selector) Selector {
+	return fieldSelector(field, "$elemMatch", selector)
+}
+
+// Text matches documents using a full-text search. It requires a text index.
+func Text(search string) Selector { return Selector{"$text": search} }
+
+// And matches documents matching all of selectors.
+func And(selectors ...Selector) Selector { return combinedSelector("$and", selectors) }
+
+// Or matches documents matching any of selectors.
+func Or(selectors ...Selector) Selector { return combinedSelector("$or", selectors) }
+
+// Nor matches documents matching none of selectors.
+func Nor(selectors ...Selector) Selector { return combinedSelector("$nor", selectors) }
+
+// Not matches documents not matching selector.
+func Not(selector Selector) Selector { return Selector{"$not": selector} }
+
+// FindQueryBuilder defines the available parameter-setting functions.
+type FindQueryBuilder interface {
+	Bookmark(string) FindQueryBuilder
+	Conflicts() FindQueryBuilder
+	ExecutionStats() FindQueryBuilder
+	Fields([]string) FindQueryBuilder
+	Limit(int) FindQueryBuilder
+	R(int) FindQueryBuilder
+	Selector(Selector) FindQueryBuilder
+	Skip(int) FindQueryBuilder
+	Sort(string, string) FindQueryBuilder
+	UseIndex(string, string) FindQueryBuilder
+	Build() *findQuery
+}
+
+type findQueryBuilder struct {
+	bookmark       string
+	conflicts      bool
+	executionStats bool
+	fields         []string
+	limit          int
+	r              int
+	selector       Selector
+	skip           int
+	sort           []map[string]string
+	useIndex       interface{}
+}
+
+// findQuery holds the implemented API call parameters. It is marshalled as
+// the JSON body of a _find request.
+type findQuery struct {
+	Selector       Selector            `json:"selector"`
+	Bookmark       string              `json:"bookmark,omitempty"`
+	Conflicts      bool                `json:"conflicts,omitempty"`
+	ExecutionStats bool                `json:"execution_stats,omitempty"`
+	Fields         []string            `json:"fields,omitempty"`
+	Limit          int                 `json:"limit,omitempty"`
+	R              int                 `json:"r,omitempty"`
+	Skip           int                 `json:"skip,omitempty"`
+	Sort           []map[string]string `json:"sort,omitempty"`
+	UseIndex       interface{}         `json:"use_index,omitempty"`
+}
+
+// NewFindQuery is the entry point.
+func NewFindQuery() FindQueryBuilder {
+	return &findQueryBuilder{}
+}
+
+func (f *findQueryBuilder) Bookmark(bookmark string) FindQueryBuilder {
+	f.bookmark = bookmark
+	return f
+}
+
+func (f *findQueryBuilder) Conflicts() FindQueryBuilder {
+	f.conflicts = true
+	return f
+}
+
+func (f *findQueryBuilder) ExecutionStats() FindQueryBuilder {
+	f.executionStats = true
+	return f
+}
+
+func (f *findQueryBuilder) Fields(fields []string) FindQueryBuilder {
+	f.fields = fields
+	return f
+}
+
+func (f *findQueryBuilder) Limit(lim int) FindQueryBuilder {
+	f.limit = lim
+	return f
+}
+
+func (f *findQueryBuilder) R(r int) FindQueryBuilder {
+	f.r = r
+	return f
+}
+
+func (f *findQueryBuilder) Selector(selector Selector) FindQueryBuilder {
+	f.selector = selector
+	return f
+}
+
+func (f *findQueryBuilder) Skip(skip int) FindQueryBuilder {
+	f.skip = skip
+	return f
+}
+
+// Sort appends a sort field. Direction is either "asc" or "desc". Sort can be
+// called multiple times to sort on more than one field.
+func (f *findQueryBuilder) Sort(field, direction string) FindQueryBuilder {
+	f.sort = append(f.sort, map[string]string{field: direction})
+	return f
+}
+
+// UseIndex names the design document, and optionally the index within it,
+// that should be used for the query. Pass an empty name to let the server
+// pick any index from the design document.
+func (f *findQueryBuilder) UseIndex(ddoc, name string) FindQueryBuilder {
+	if name == "" {
+		f.useIndex = ddoc
+	} else {
+		f.useIndex = []string{ddoc, name}
+	}
+	return f
+}
+
+func (f *findQueryBuilder) Build() *findQuery {
+	selector := f.selector
+	if selector == nil {
+		selector = Selector{}
+	}
+
+	return &findQuery{
+		Selector:       selector,
+		Bookmark:       f.bookmark,
+		Conflicts:      f.conflicts,
+		ExecutionStats: f.executionStats,
+		Fields:         f.fields,
+		Limit:          f.limit,
+		R:              f.r,
+		Skip:           f.skip,
+		Sort:           f.sort,
+		UseIndex:       f.useIndex,
+	}
+}
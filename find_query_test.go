@@ -0,0 +1,82 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestFindQuery_Args(t *testing.T) {
+	// Selector       Selector
+	// Bookmark       string
+	// Conflicts      bool
+	// ExecutionStats bool
+	// Fields         []string
+	// Limit          int
+	// R              int
+	// Skip           int
+	// Sort           []map[string]string
+	// UseIndex       interface{}
+
+	expectedBodyStrings := []string{
+		`"selector":{"foo":{"$eq":"bar"}}`,
+		`"bookmark":"g1AAAA"`,
+		`"conflicts":true`,
+		`"execution_stats":true`,
+		`"fields":["_id","foo"]`,
+		`"limit":5`,
+		`"r":2`,
+		`"skip":32`,
+		`"sort":[{"foo":"asc"},{"bar":"desc"}]`,
+		`"use_index":["myddoc","myindex"]`,
+	}
+
+	query := NewFindQuery().
+		Selector(Eq("foo", "bar")).
+		Bookmark("g1AAAA").
+		Conflicts().
+		ExecutionStats().
+		Fields([]string{"_id", "foo"}).
+		Limit(5).
+		R(2).
+		Skip(32).
+		Sort("foo", "asc").
+		Sort("bar", "desc").
+		UseIndex("myddoc", "myindex").
+		Build()
+
+	data, _ := json.Marshal(query)
+	body := string(data)
+
+	for _, str := range expectedBodyStrings {
+		if !strings.Contains(body, str) {
+			t.Errorf("parameter encoding not found '%s' in '%s'", str, body)
+			return
+		}
+	}
+}
+
+func TestFindQuery_Selector(t *testing.T) {
+	selector := And(
+		In("type", "user", "admin"),
+		Or(Gt("age", 21), Regex("name", "^A")),
+		ElemMatch("tags", Eq("", "go")),
+		Text("cloudant"),
+	)
+
+	expected := `{"$and":[` +
+		`{"type":{"$in":["user","admin"]}},` +
+		`{"$or":[{"age":{"$gt":21}},{"name":{"$regex":"^A"}}]},` +
+		`{"tags":{"$elemMatch":{"":{"$eq":"go"}}}},` +
+		`{"$text":"cloudant"}]}`
+
+	data, _ := json.Marshal(selector)
+	if string(data) != expected {
+		t.Errorf("unexpected selector encoding '%s'", data)
+	}
+
+	data, _ = json.Marshal(NewFindQuery().Build())
+	if string(data) != `{"selector":{}}` {
+		t.Errorf("unexpected default query encoding '%s'", data)
+	}
+}
@@ -0,0 +1,119 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestFindResponse_Decode(t *testing.T) {
+	body := `{"docs":[
+{"_id":"doc-001","foo":"bar"},
+{"_id":"doc-002","foo":"bar"}
+],
+"bookmark": "g1AAAABweJzLYWBgYMpgSmHgKy5JLCrJTq2MT8lPzkzJBYqzJ-ekJucb",
+"execution_stats": {"total_keys_examined":0,"total_docs_examined":2,"total_quorum_docs_examined":0,"results_returned":2,"execution_time_ms":1.2},
+"warning": "no matching index found, create an index to optimize query time"}
+`
+	docs := make(chan json.RawMessage, 10)
+	response := &FindResponse{}
+
+	if err := decodeFindResponse(strings.NewReader(body), docs, response); err != nil {
+		t.Fatal(err)
+	}
+	close(docs)
+
+	i := 0
+	for doc := range docs {
+		i++
+		d := &cloudantDocument{}
+		if err := json.Unmarshal(doc, d); err != nil || d.ID != fmt.Sprintf("doc-%.3d", i) {
+			t.Errorf("unexpected document %s", doc)
+		}
+	}
+	if 2 != i {
+		t.Errorf("unexpected number of docs received %d", i)
+	}
+	if !strings.HasPrefix(response.Bookmark, "g1AAAAB") {
+		t.Errorf("unexpected bookmark %s", response.Bookmark)
+	}
+	if !strings.HasPrefix(response.Warning, "no matching index") {
+		t.Errorf("unexpected warning %s", response.Warning)
+	}
+	if response.ExecutionStats == nil || response.ExecutionStats.ResultsReturned != 2 {
+		t.Errorf("unexpected execution stats %+v", response.ExecutionStats)
+	}
+}
+
+func TestDatabase_Find(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	makeDocuments(database, 100)
+
+	query := NewFindQuery().
+		Selector(And(Gte("_id", "doc-010"), Lt("_id", "doc-020"))).
+		Fields([]string{"_id", "bar"}).
+		Build()
+
+	response, err := database.Find(query)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	i := 0
+	for range response.Docs {
+		i++
+	}
+	if response.Err != nil {
+		t.Error(response.Err)
+	}
+	if 10 != i {
+		t.Errorf("unexpected number of docs received %d", i)
+	}
+	if response.Bookmark == "" {
+		t.Error("missing bookmark")
+	}
+}
+
+func TestDatabase_FindPager(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	makeDocuments(database, 100)
+
+	query := NewFindQuery().
+		Selector(Eq("bar", 123)).
+		Limit(30).
+		Build()
+
+	pager := NewFindPager(database, query)
+
+	pages, docs := 0, 0
+	for pager.Next() {
+		pages++
+		docs += len(pager.Docs())
+	}
+	if err := pager.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if 4 != pages {
+		t.Errorf("unexpected number of pages received %d", pages)
+	}
+	if 100 != docs {
+		t.Errorf("unexpected number of docs received %d", docs)
+	}
+}
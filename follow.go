@@ -71,6 +71,22 @@ func (f *Follower) Close() {
 
 // Follow starts listening to the changes feed
 func (f *Follower) Follow() (<-chan *ChangeEvent, error) {
+	changes := make(chan *ChangeEvent, 1000)
+
+	err := f.follow(func(event *ChangeEvent, line []byte) {
+		changes <- event
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return changes, nil
+}
+
+// follow starts listening to the changes feed, passing each event to send
+// along with the line of the feed it was decoded from, or nil for events
+// that weren't read from a line.
+func (f *Follower) follow(send func(event *ChangeEvent, line []byte)) error {
 	query := NewChangesQuery().
 		IncludeDocs().
 		Feed("continuous").
@@ -86,22 +102,21 @@ func (f *Follower) Follow() (<-chan *ChangeEvent, error) {
 
 	urlStr, err := Endpoint(*f.db.URL, "/_changes", params)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	job, err := f.db.client.request("GET", urlStr, nil)
 	if err != nil {
 		job.Close()
-		return nil, err
+		return err
 	}
 
 	err = expectedReturnCodes(job, 200)
 	if err != nil {
 		job.Close()
-		return nil, err
+		return err
 	}
 
-	changes := make(chan *ChangeEvent, 1000)
 	go func() {
 		defer job.Close()
 		defer close(f.stopped) // This lets consumers block until terminated
@@ -113,12 +128,12 @@ func (f *Follower) Follow() (<-chan *ChangeEvent, error) {
 			default:
 				line, err := reader.ReadBytes('\n')
 				if err != nil {
-					changes <- &ChangeEvent{EventType: ChangesTerminated}
+					send(&ChangeEvent{EventType: ChangesTerminated}, nil)
 					return
 				}
 				lineStr := strings.TrimSpace(string(line))
 				if lineStr == "" {
-					changes <- &ChangeEvent{EventType: ChangesHeartbeat}
+					send(&ChangeEvent{EventType: ChangesHeartbeat}, nil)
 					continue
 				}
 				if len(lineStr) > 7 && lineStr[0:7] == "{\"seq\":" {
@@ -132,7 +147,7 @@ func (f *Follower) Follow() (<-chan *ChangeEvent, error) {
 						if change.Seq != "null" {
 							f.since = change.Seq
 						}
-						changes <- &ChangeEvent{
+						send(&ChangeEvent{
 							EventType: eventType(change),
 							Meta: &DocumentMeta{
 								ID:  change.ID,
@@ -140,12 +155,12 @@ func (f *Follower) Follow() (<-chan *ChangeEvent, error) {
 							},
 							Seq: change.Seq,
 							Doc: change.Doc,
-						}
+						}, []byte(lineStr))
 					} else {
-						changes <- &ChangeEvent{
+						send(&ChangeEvent{
 							EventType: ChangesError,
 							Err:       err,
-						}
+						}, nil)
 					}
 				}
 			case <-f.stop:
@@ -154,5 +169,5 @@ func (f *Follower) Follow() (<-chan *ChangeEvent, error) {
 		}
 	}()
 
-	return changes, nil
+	return nil
 }
@@ -0,0 +1,69 @@
+package cloudant
+
+import (
+	"encoding/json"
+)
+
+// Geometry is a GeoJSON geometry object. Coordinates are left as raw JSON as
+// their shape depends on the geometry type.
+type Geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// GeoRow represents a single geo query result in the default "view" format
+type GeoRow struct {
+	ID       string          `json:"id"`
+	Rev      string          `json:"rev"`
+	Geometry Geometry        `json:"geometry"`
+	Doc      json.RawMessage `json:"doc"` // Only present if IncludeDocs() was set
+}
+
+// GeoFeature represents a single geo query result in the "geojson" format
+type GeoFeature struct {
+	Type       string                 `json:"type"`
+	ID         string                 `json:"_id"`
+	Rev        string                 `json:"_rev"`
+	Geometry   Geometry               `json:"geometry"`
+	Properties map[string]interface{} `json:"properties"`
+	Doc        json.RawMessage        `json:"doc"` // Only present if IncludeDocs() was set
+}
+
+// GeoResponse is the JSON body of the response from a geo index. Rows is
+// populated for the GeoFormatView format and Features for GeoFormatGeoJSON.
+type GeoResponse struct {
+	Bookmark string       `json:"bookmark"`
+	Rows     []GeoRow     `json:"rows"`
+	Type     string       `json:"type"` // "FeatureCollection" for GeoFormatGeoJSON
+	Features []GeoFeature `json:"features"`
+}
+
+// Geo queries a Cloudant Geospatial index.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/cloudant-geo.html#querying-a-cloudant-geo-index
+func (d *Database) Geo(ddoc, index string, args *geoQuery) (*GeoResponse, error) {
+	params, err := args.GetQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*d.URL, designDocID(ddoc)+"/_geo/"+index, params)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("GET", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &GeoResponse{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+
+	return resp, err
+}
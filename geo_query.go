@@ -0,0 +1,275 @@
+package cloudant
+
+// QueryBuilder implementation for the Geo() API call.
+//
+// Example:
+// 	query := NewGeoQuery().
+//     Radius(51.4545, -2.5879, 1000).
+//     Nearest().
+//     IncludeDocs().
+//     Build()
+//
+//	result, err := db.Geo("mydesigndoc", "myindex", query)
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Geometric relations between a query geometry (bbox, radius, ellipse or g)
+// and the indexed geometries
+const (
+	GeoRelationContains   = "contains"
+	GeoRelationCrosses    = "crosses"
+	GeoRelationDisjoint   = "disjoint"
+	GeoRelationIntersects = "intersects"
+	GeoRelationOverlaps   = "overlaps"
+	GeoRelationTouches    = "touches"
+	GeoRelationWithin     = "within"
+)
+
+// Result formats of a geo query
+const (
+	GeoFormatView    = "view"
+	GeoFormatGeoJSON = "geojson"
+)
+
+// GeoQueryBuilder defines the available parameter-setting functions.
+type GeoQueryBuilder interface {
+	BBox(float64, float64, float64, float64) GeoQueryBuilder
+	Bookmark(string) GeoQueryBuilder
+	Ellipse(float64, float64, float64, float64) GeoQueryBuilder
+	Format(string) GeoQueryBuilder
+	G(string) GeoQueryBuilder
+	IncludeDocs() GeoQueryBuilder
+	Limit(int) GeoQueryBuilder
+	Nearest() GeoQueryBuilder
+	Polygon([][2]float64) GeoQueryBuilder
+	Radius(float64, float64, float64) GeoQueryBuilder
+	Relation(string) GeoQueryBuilder
+	Skip(int) GeoQueryBuilder
+	Stale() GeoQueryBuilder
+	Build() *geoQuery
+}
+
+type geoQueryBuilder struct {
+	bbox        []float64
+	bookmark    string
+	format      string
+	g           string
+	includeDocs bool
+	lat         *float64
+	limit       int
+	lon         *float64
+	nearest     bool
+	radius      float64
+	rangeX      float64
+	rangeY      float64
+	relation    string
+	skip        int
+	stale       bool
+}
+
+// geoQuery holds the implemented API call parameters. Lat, Lon, Radius,
+// RangeX and RangeY describe either a circle or an ellipse around a point.
+type geoQuery struct {
+	BBox        []float64
+	Bookmark    string
+	Format      string
+	G           string
+	IncludeDocs bool
+	Lat         *float64
+	Limit       int
+	Lon         *float64
+	Nearest     bool
+	Radius      float64
+	RangeX      float64
+	RangeY      float64
+	Relation    string
+	Skip        int
+	Stale       bool
+}
+
+// NewGeoQuery is the entry point.
+func NewGeoQuery() GeoQueryBuilder {
+	return &geoQueryBuilder{}
+}
+
+// BBox queries a bounding box, given as the coordinates of its lower-left and
+// upper-right corners.
+func (g *geoQueryBuilder) BBox(minLon, minLat, maxLon, maxLat float64) GeoQueryBuilder {
+	g.bbox = []float64{minLon, minLat, maxLon, maxLat}
+	return g
+}
+
+func (g *geoQueryBuilder) Bookmark(bookmark string) GeoQueryBuilder {
+	g.bookmark = bookmark
+	return g
+}
+
+// Ellipse queries an ellipse centred on lat/lon, with ranges in metres.
+func (g *geoQueryBuilder) Ellipse(lat, lon, rangeX, rangeY float64) GeoQueryBuilder {
+	g.lat = &lat
+	g.lon = &lon
+	g.rangeX = rangeX
+	g.rangeY = rangeY
+	return g
+}
+
+// Format is either GeoFormatView (the default) or GeoFormatGeoJSON.
+func (g *geoQueryBuilder) Format(format string) GeoQueryBuilder {
+	g.format = format
+	return g
+}
+
+// G queries an arbitrary Well Known Text geometry, such as a point,
+// linestring or polygon.
+func (g *geoQueryBuilder) G(wkt string) GeoQueryBuilder {
+	g.g = wkt
+	return g
+}
+
+func (g *geoQueryBuilder) IncludeDocs() GeoQueryBuilder {
+	g.includeDocs = true
+	return g
+}
+
+func (g *geoQueryBuilder) Limit(lim int) GeoQueryBuilder {
+	g.limit = lim
+	return g
+}
+
+// Nearest sorts the results by distance from the query geometry.
+func (g *geoQueryBuilder) Nearest() GeoQueryBuilder {
+	g.nearest = true
+	return g
+}
+
+// Polygon queries the polygon with the given lon/lat vertices. The ring is
+// closed automatically if the last vertex differs from the first.
+func (g *geoQueryBuilder) Polygon(points [][2]float64) GeoQueryBuilder {
+	if len(points) > 0 && points[0] != points[len(points)-1] {
+		// close the ring on a copy, so the caller's slice isn't written to
+		points = append(append(make([][2]float64, 0, len(points)+1), points...), points[0])
+	}
+
+	coords := make([]string, len(points))
+	for i, point := range points {
+		coords[i] = fmt.Sprintf("%s %s", formatCoord(point[0]), formatCoord(point[1]))
+	}
+
+	g.g = "POLYGON((" + strings.Join(coords, ",") + "))"
+	return g
+}
+
+// Radius queries a circle centred on lat/lon, with a radius in metres.
+func (g *geoQueryBuilder) Radius(lat, lon, radius float64) GeoQueryBuilder {
+	g.lat = &lat
+	g.lon = &lon
+	g.radius = radius
+	return g
+}
+
+// Relation is one of the GeoRelation constants. The server defaults to
+// GeoRelationIntersects.
+func (g *geoQueryBuilder) Relation(relation string) GeoQueryBuilder {
+	g.relation = relation
+	return g
+}
+
+func (g *geoQueryBuilder) Skip(skip int) GeoQueryBuilder {
+	g.skip = skip
+	return g
+}
+
+func (g *geoQueryBuilder) Stale() GeoQueryBuilder {
+	g.stale = true
+	return g
+}
+
+func formatCoord(coord float64) string {
+	return strconv.FormatFloat(coord, 'f', -1, 64)
+}
+
+// GetQuery implements the QueryBuilder interface. It returns an
+// url.Values map with the non-default values set.
+func (gq *geoQuery) GetQuery() (url.Values, error) {
+	vals := url.Values{}
+
+	if len(gq.BBox) > 0 {
+		if len(gq.BBox) != 4 {
+			return nil, fmt.Errorf("bbox needs 4 coordinates, got %d", len(gq.BBox))
+		}
+		coords := make([]string, len(gq.BBox))
+		for i, coord := range gq.BBox {
+			coords[i] = formatCoord(coord)
+		}
+		vals.Set("bbox", strings.Join(coords, ","))
+	}
+	if gq.Bookmark != "" {
+		vals.Set("bookmark", gq.Bookmark)
+	}
+	if gq.Format != "" {
+		vals.Set("format", gq.Format)
+	}
+	if gq.G != "" {
+		vals.Set("g", gq.G)
+	}
+	if gq.IncludeDocs {
+		vals.Set("include_docs", "true")
+	}
+	if gq.Lat != nil {
+		vals.Set("lat", formatCoord(*gq.Lat))
+	}
+	if gq.Limit > 0 {
+		vals.Set("limit", strconv.Itoa(gq.Limit))
+	}
+	if gq.Lon != nil {
+		vals.Set("lon", formatCoord(*gq.Lon))
+	}
+	if gq.Nearest {
+		vals.Set("nearest", "true")
+	}
+	if gq.Radius > 0 {
+		vals.Set("radius", formatCoord(gq.Radius))
+	}
+	if gq.RangeX > 0 {
+		vals.Set("rangex", formatCoord(gq.RangeX))
+	}
+	if gq.RangeY > 0 {
+		vals.Set("rangey", formatCoord(gq.RangeY))
+	}
+	if gq.Relation != "" {
+		vals.Set("relation", gq.Relation)
+	}
+	if gq.Skip > 0 {
+		vals.Set("skip", strconv.Itoa(gq.Skip))
+	}
+	if gq.Stale {
+		vals.Set("stale", "ok")
+	}
+
+	return vals, nil
+}
+
+func (g *geoQueryBuilder) Build() *geoQuery {
+	return &geoQuery{
+		BBox:        g.bbox,
+		Bookmark:    g.bookmark,
+		Format:      g.format,
+		G:           g.g,
+		IncludeDocs: g.includeDocs,
+		Lat:         g.lat,
+		Limit:       g.limit,
+		Lon:         g.lon,
+		Nearest:     g.nearest,
+		Radius:      g.radius,
+		RangeX:      g.rangeX,
+		RangeY:      g.rangeY,
+		Relation:    g.relation,
+		Skip:        g.skip,
+		Stale:       g.stale,
+	}
+}
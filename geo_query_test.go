@@ -0,0 +1,91 @@
+package cloudant
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGeoQuery_Args(t *testing.T) {
+	// Bookmark    string
+	// Format      string
+	// IncludeDocs bool
+	// Lat         *float64
+	// Limit       int
+	// Lon         *float64
+	// Nearest     bool
+	// Radius      float64
+	// Relation    string
+	// Skip        int
+	// Stale       bool
+
+	expectedQueryStrings := []string{
+		"bookmark=g1AAAA",
+		"format=geojson",
+		"include_docs=true",
+		"lat=51.4545",
+		"limit=5",
+		"lon=-2.5879",
+		"nearest=true",
+		"radius=1000",
+		"relation=within",
+		"skip=32",
+		"stale=ok",
+	}
+
+	query := NewGeoQuery().
+		Bookmark("g1AAAA").
+		Format(GeoFormatGeoJSON).
+		IncludeDocs().
+		Limit(5).
+		Nearest().
+		Radius(51.4545, -2.5879, 1000).
+		Relation(GeoRelationWithin).
+		Skip(32).
+		Stale().
+		Build()
+
+	values, _ := query.GetQuery()
+	queryString := values.Encode()
+
+	for _, str := range expectedQueryStrings {
+		if !strings.Contains(queryString, str) {
+			t.Errorf("parameter encoding not found '%s' in '%s'", str, queryString)
+			return
+		}
+	}
+}
+
+func TestGeoQuery_Shapes(t *testing.T) {
+	values, _ := NewGeoQuery().BBox(-11.05, 49.69, 2.1, 61.21).Build().GetQuery()
+	if values.Get("bbox") != "-11.05,49.69,2.1,61.21" {
+		t.Errorf("unexpected bbox encoding '%s'", values.Get("bbox"))
+	}
+
+	values, _ = NewGeoQuery().Ellipse(42.3, -71.1, 100, 200).Build().GetQuery()
+	if values.Get("lat") != "42.3" || values.Get("rangex") != "100" || values.Get("rangey") != "200" {
+		t.Errorf("unexpected ellipse encoding '%s'", values.Encode())
+	}
+
+	query := NewGeoQuery().
+		Polygon([][2]float64{{-71.1, 42.3}, {-71.0, 42.3}, {-71.0, 42.4}}).
+		Relation(GeoRelationContains).
+		Build()
+	values, _ = query.GetQuery()
+	if values.Get("g") != "POLYGON((-71.1 42.3,-71 42.3,-71 42.4,-71.1 42.3))" {
+		t.Errorf("unexpected polygon encoding '%s'", values.Get("g"))
+	}
+
+	// closing the ring must not write into spare capacity of the caller's slice
+	points := make([][2]float64, 3, 4)
+	copy(points, [][2]float64{{1, 1}, {2, 1}, {2, 2}})
+	spare := points[:4]
+	spare[3] = [2]float64{9, 9}
+	NewGeoQuery().Polygon(points)
+	if spare[3] != [2]float64{9, 9} {
+		t.Errorf("Polygon modified the caller's slice: %v", spare)
+	}
+
+	if _, err := (&geoQuery{BBox: []float64{1, 2}}).GetQuery(); err == nil {
+		t.Error("expected an error for an incomplete bbox")
+	}
+}
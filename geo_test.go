@@ -0,0 +1,41 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestGeoResponse_Decode(t *testing.T) {
+	view := []byte(`{"bookmark": "g1AAAAB", "rows": [
+		{"id": "doc-001", "rev": "1-abc", "geometry": {"type": "Point", "coordinates": [-71.1, 42.3]}}
+	]}`)
+
+	resp := &GeoResponse{}
+	if err := json.Unmarshal(view, resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Bookmark != "g1AAAAB" || len(resp.Rows) != 1 || resp.Rows[0].Geometry.Type != "Point" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+
+	var coords []float64
+	if err := json.Unmarshal(resp.Rows[0].Geometry.Coordinates, &coords); err != nil || coords[1] != 42.3 {
+		t.Errorf("unexpected coordinates %s", resp.Rows[0].Geometry.Coordinates)
+	}
+
+	geojson := []byte(`{"type": "FeatureCollection", "bookmark": "g1AAAAB", "features": [
+		{"type": "Feature", "_id": "doc-001", "_rev": "1-abc",
+		 "geometry": {"type": "Point", "coordinates": [-71.1, 42.3]}, "properties": {"name": "Boston"}}
+	]}`)
+
+	resp = &GeoResponse{}
+	if err := json.Unmarshal(geojson, resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Type != "FeatureCollection" || len(resp.Features) != 1 || resp.Features[0].ID != "doc-001" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if resp.Features[0].Properties["name"] != "Boston" {
+		t.Errorf("unexpected properties %+v", resp.Features[0].Properties)
+	}
+}
@@ -145,7 +145,7 @@ func (gq *getQuery) GetQuery() (url.Values, error) {
 		if err != nil {
 			return nil, err
 		}
-		vals.Set("attsSince", string(data[:]))
+		vals.Set("atts_since", string(data[:]))
 	}
 	if gq.Conflicts {
 		vals.Set("conflicts", "true")
@@ -55,3 +55,18 @@ func TestGetQuery_GetArgs(t *testing.T) {
 		}
 	}
 }
+
+func TestGetQuery_AttsSince(t *testing.T) {
+	query := NewGetQuery().
+		AttsSince([]string{"1-abc", "2-def"}).
+		Build()
+
+	values, _ := query.GetQuery()
+
+	if values.Get("atts_since") != `["1-abc","2-def"]` {
+		t.Errorf("unexpected atts_since parameter '%s' in '%s'", values.Get("atts_since"), values.Encode())
+	}
+	if _, ok := values["attsSince"]; ok {
+		t.Errorf("unexpected attsSince parameter in '%s'", values.Encode())
+	}
+}
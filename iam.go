@@ -0,0 +1,221 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// IAMTokenURL is the IBM Cloud IAM endpoint used by CreateIAMClient() to
+// exchange API keys for tokens.
+var IAMTokenURL = "https://iam.cloud.ibm.com/identity/token"
+
+// Tokens are renewed once this fraction of their lifetime has passed, and
+// failed renewals are retried after iamRetryDelay.
+var iamRefreshFraction = 0.8
+var iamRetryDelay = 10 * time.Second
+
+// iamTokenResponse is the JSON body of a response from the IAM token endpoint
+type iamTokenResponse struct {
+	AccessToken  string `json:"access_token"`
+	ExpiresIn    int    `json:"expires_in"`
+	ErrorMessage string `json:"errorMessage"`
+}
+
+// iamAuth holds the IAM token of a client, and renews it in the background
+// before it expires. Tokens are fetched without holding the lock, so requests
+// carry on with the current token while a new one is on its way.
+type iamAuth struct {
+	apiKey     string
+	tokenURL   string
+	httpClient *http.Client // kept apart from the client's, which holds session cookies
+
+	mu        sync.RWMutex
+	current   string
+	refreshAt time.Time
+	inflight  *iamRefresh // the refresh in progress, if any
+
+	stopOnce sync.Once
+	stopChan chan struct{}
+}
+
+// iamRefresh is a token refresh shared by all the callers asking for one
+// while it is in progress.
+type iamRefresh struct {
+	done chan struct{}
+	err  error
+}
+
+// CreateIAMClient returns a new client authenticating with an IBM Cloud IAM
+// API key (with max. retry 3 using a random 5-30 secs delay). Requests carry
+// an IAM token, which is renewed before it expires and whenever the server
+// rejects it.
+func CreateIAMClient(apiKey, rootStrURL string, concurrency int) (*CouchClient, error) {
+	if concurrency <= 0 {
+		return nil, fmt.Errorf("Concurrency must be >= 1")
+	}
+	return CreateIAMClientWithRetry(apiKey, IAMTokenURL, rootStrURL, concurrency, 3, 5, 30)
+}
+
+// CreateIAMClientWithRetry returns a new client authenticating with an IBM
+// Cloud IAM API key, with a configurable token endpoint and retry parameters.
+func CreateIAMClientWithRetry(apiKey, tokenURL, rootStrURL string, concurrency, retryCountMax,
+	retryDelayMin, retryDelayMax int) (*CouchClient, error) {
+
+	couchClient, err := newClient(rootStrURL, concurrency, retryCountMax, retryDelayMin, retryDelayMax)
+	if err != nil {
+		return nil, err
+	}
+
+	couchClient.iam = &iamAuth{
+		apiKey:     apiKey,
+		tokenURL:   tokenURL,
+		httpClient: &http.Client{Timeout: transportTimeout},
+		stopChan:   make(chan struct{}),
+	}
+
+	err = couchClient.iam.refresh() // get initial token
+	if err != nil {
+		return nil, err
+	}
+
+	startDispatcher(couchClient) // start workers
+	go couchClient.iam.run()
+
+	return couchClient, nil
+}
+
+// token returns the current token.
+func (a *iamAuth) token() string {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.current
+}
+
+// refresh exchanges the API key for a new token.
+func (a *iamAuth) refresh() error {
+	return a.refreshIf(func() bool { return true })
+}
+
+// renew gets a new token after the server rejected the one sent, unless it
+// has already been replaced.
+func (a *iamAuth) renew(sent string) error {
+	return a.refreshIf(func() bool { return a.current == sent })
+}
+
+// refreshIf gets a new token if needed, which is called with the lock held,
+// reports so. If a refresh is already in progress it waits for that one
+// instead.
+func (a *iamAuth) refreshIf(needed func() bool) error {
+	a.mu.Lock()
+	if call := a.inflight; call != nil {
+		a.mu.Unlock()
+		<-call.done
+		return call.err
+	}
+	if !needed() {
+		a.mu.Unlock()
+		return nil
+	}
+	call := &iamRefresh{done: make(chan struct{})}
+	a.inflight = call
+	a.mu.Unlock()
+
+	token, lifetime, err := a.fetch()
+
+	a.mu.Lock()
+	if err == nil {
+		a.current = token
+		a.refreshAt = time.Now().Add(lifetime)
+	}
+	a.inflight = nil
+	a.mu.Unlock()
+
+	call.err = err
+	close(call.done)
+
+	return err
+}
+
+// fetch gets a new token from the token endpoint, returning it with the time
+// after which it should be renewed.
+func (a *iamAuth) fetch() (string, time.Duration, error) {
+	data := url.Values{}
+	data.Add("grant_type", "urn:ibm:params:oauth:grant-type:apikey")
+	data.Add("apikey", a.apiKey)
+
+	req, err := http.NewRequest("POST", a.tokenURL, strings.NewReader(data.Encode()))
+	if err != nil {
+		return "", 0, err
+	}
+
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Add("Accept", "application/json")
+
+	resp, err := a.httpClient.Do(req)
+	if err != nil {
+		return "", 0, err
+	}
+	defer resp.Body.Close()
+
+	tokenResponse := &iamTokenResponse{}
+	err = json.NewDecoder(resp.Body).Decode(tokenResponse)
+
+	if resp.StatusCode != 200 {
+		if err == nil && tokenResponse.ErrorMessage != "" {
+			return "", 0, fmt.Errorf("failed to get IAM token, status %d: %s",
+				resp.StatusCode, tokenResponse.ErrorMessage)
+		}
+		return "", 0, fmt.Errorf("failed to get IAM token, status %d", resp.StatusCode)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	if tokenResponse.AccessToken == "" {
+		return "", 0, fmt.Errorf("failed to get IAM token, no token in response")
+	}
+
+	lifetime := time.Duration(float64(tokenResponse.ExpiresIn) * iamRefreshFraction * float64(time.Second))
+	if lifetime < time.Second {
+		lifetime = time.Second // don't hammer the endpoint with short-lived tokens
+	}
+
+	return tokenResponse.AccessToken, lifetime, nil
+}
+
+// run renews the token in the background until stop() is called.
+func (a *iamAuth) run() {
+	for {
+		a.mu.RLock()
+		wait := a.refreshAt.Sub(time.Now())
+		a.mu.RUnlock()
+
+		timer := time.NewTimer(wait)
+
+		select {
+		case <-timer.C:
+			if err := a.refresh(); err != nil {
+				LogFunc("failed to renew IAM token, %s", err)
+
+				a.mu.Lock()
+				a.refreshAt = time.Now().Add(iamRetryDelay)
+				a.mu.Unlock()
+			}
+		case <-a.stopChan:
+			timer.Stop()
+			return
+		}
+	}
+}
+
+// stop ends background renewal.
+func (a *iamAuth) stop() {
+	a.stopOnce.Do(func() {
+		close(a.stopChan)
+	})
+}
@@ -0,0 +1,196 @@
+package cloudant
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// iamTokenServer is a fake IAM token endpoint, issuing numbered tokens.
+type iamTokenServer struct {
+	mu        sync.Mutex
+	issued    int
+	expiresIn int
+	delay     time.Duration // time taken to issue a token
+	cookies   int           // requests received with cookies
+}
+
+func (s *iamTokenServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	time.Sleep(s.delay)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(r.Cookies()) > 0 {
+		s.cookies++
+	}
+
+	r.ParseForm()
+	if r.Form.Get("grant_type") != "urn:ibm:params:oauth:grant-type:apikey" || r.Form.Get("apikey") != "my-key" {
+		w.WriteHeader(400)
+		fmt.Fprint(w, `{"errorCode":"BXNIM0415E","errorMessage":"Provided API key could not be found"}`)
+		return
+	}
+
+	s.issued++
+	fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":%d}`, s.issued, s.expiresIn)
+}
+
+func (s *iamTokenServer) latest() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return fmt.Sprintf("token-%d", s.issued)
+}
+
+// iamCouchServer is a stand-in for a server accepting only the latest token.
+func iamCouchServer(tokens *iamTokenServer, sent *[]string) *httptest.Server {
+	var mu sync.Mutex
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := r.Header.Get("Authorization")
+
+		mu.Lock()
+		*sent = append(*sent, auth)
+		mu.Unlock()
+
+		if auth != "Bearer "+tokens.latest() {
+			w.WriteHeader(401)
+			fmt.Fprint(w, `{"error":"unauthorized","reason":"invalid token"}`)
+			return
+		}
+		http.SetCookie(w, &http.Cookie{Name: "AuthSession", Value: "session"})
+		fmt.Fprint(w, `{"couchdb":"Welcome"}`)
+	}))
+}
+
+func TestIAMClient_Token(t *testing.T) {
+	tokens := &iamTokenServer{expiresIn: 3600}
+	tokenServer := httptest.NewServer(tokens)
+	defer tokenServer.Close()
+
+	sent := []string{}
+	server := iamCouchServer(tokens, &sent)
+	defer server.Close()
+
+	client, err := CreateIAMClientWithRetry("my-key", tokenServer.URL, server.URL, 1, 1, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	if err = client.Ping(); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(sent) != 1 || sent[0] != "Bearer token-1" {
+		t.Errorf("unexpected Authorization headers %v", sent)
+	}
+
+	// a token revoked by the server is renewed and the request retried
+	tokens.mu.Lock()
+	tokens.issued++
+	tokens.mu.Unlock()
+
+	if err = client.Ping(); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(sent) != 3 || sent[1] != "Bearer token-1" || sent[2] != "Bearer token-3" {
+		t.Errorf("unexpected Authorization headers %v", sent)
+	}
+}
+
+func TestIAMClient_BackgroundRefresh(t *testing.T) {
+	tokens := &iamTokenServer{expiresIn: 1}
+	tokenServer := httptest.NewServer(tokens)
+	defer tokenServer.Close()
+
+	sent := []string{}
+	server := iamCouchServer(tokens, &sent)
+	defer server.Close()
+
+	client, err := CreateIAMClientWithRetry("my-key", tokenServer.URL, server.URL, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	time.Sleep(1500 * time.Millisecond)
+
+	if token := client.iam.token(); token == "token-1" {
+		t.Errorf("expected the token to be renewed in the background")
+	}
+	if err = client.Ping(); err != nil {
+		t.Fatalf("%s", err)
+	}
+}
+
+func TestIAMAuth_Renew(t *testing.T) {
+	tokens := &iamTokenServer{expiresIn: 3600}
+	tokenServer := httptest.NewServer(tokens)
+	defer tokenServer.Close()
+
+	sent := []string{}
+	server := iamCouchServer(tokens, &sent)
+	defer server.Close()
+
+	client, err := CreateIAMClientWithRetry("my-key", tokenServer.URL, server.URL, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	if err = client.Ping(); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	tokens.mu.Lock()
+	tokens.delay = 200 * time.Millisecond
+	tokens.mu.Unlock()
+
+	// concurrent renewals of the same token share a single refresh
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.iam.renew("token-1"); err != nil {
+				t.Errorf("%s", err)
+			}
+		}()
+	}
+
+	// the current token stays available while the refresh is in progress
+	time.Sleep(50 * time.Millisecond)
+	start := time.Now()
+	if token := client.iam.token(); token != "token-1" {
+		t.Errorf("unexpected token %s during refresh", token)
+	}
+	if elapsed := time.Since(start); elapsed > 50*time.Millisecond {
+		t.Errorf("reading the token blocked for %s", elapsed)
+	}
+
+	wg.Wait()
+
+	tokens.mu.Lock()
+	defer tokens.mu.Unlock()
+
+	if tokens.issued != 2 || client.iam.token() != "token-2" {
+		t.Errorf("expected a single refresh, %d tokens issued", tokens.issued)
+	}
+	if tokens.cookies != 0 {
+		t.Errorf("expected no session cookies to be sent to the token endpoint")
+	}
+}
+
+func TestIAMClient_InvalidKey(t *testing.T) {
+	tokenServer := httptest.NewServer(&iamTokenServer{})
+	defer tokenServer.Close()
+
+	_, err := CreateIAMClientWithRetry("wrong-key", tokenServer.URL, "http://localhost:5984", 1, 0, 0, 1)
+	if err == nil {
+		t.Errorf("expected an error for an invalid API key")
+	}
+}
@@ -0,0 +1,266 @@
+package cloudant
+
+// Builder implementation for the CreateIndex() API call.
+//
+// Example:
+// 	index := NewIndex().
+//     DesignDoc("by-type").
+//     Name("type-date").
+//     Field("type", "asc").
+//     Field("date", "asc").
+//     PartialFilterSelector(Exists("date", true)).
+//     Build()
+//
+//	result, err := db.CreateIndex(index)
+//	if result.Result == IndexCreated {
+//		...
+//	}
+
+import (
+	"bytes"
+	"encoding/json"
+	"path"
+)
+
+// Index types
+const (
+	IndexTypeJSON = "json"
+	IndexTypeText = "text"
+)
+
+// Possible values of CreateIndexResponse.Result
+const (
+	IndexCreated = "created"
+	IndexExists  = "exists"
+)
+
+// Index is a Cloudant Query index as returned by ListIndexes().
+type Index struct {
+	DesignDoc   string          `json:"ddoc"` // Empty for the special _all_docs index
+	Name        string          `json:"name"`
+	Type        string          `json:"type"`
+	Partitioned bool            `json:"partitioned"`
+	Def         IndexDefinition `json:"def"`
+}
+
+// IndexDefinition describes the fields covered by an index. Fields maps each
+// field name to its sort direction for json indexes, or to its type for text
+// indexes.
+type IndexDefinition struct {
+	Fields                []map[string]interface{} `json:"fields,omitempty"`
+	PartialFilterSelector Selector                 `json:"partial_filter_selector,omitempty"`
+	DefaultAnalyzer       interface{}              `json:"default_analyzer,omitempty"`
+	DefaultField          interface{}              `json:"default_field,omitempty"`
+	IndexArrayLengths     *bool                    `json:"index_array_lengths,omitempty"`
+	Selector              Selector                 `json:"selector,omitempty"`
+}
+
+// CreateIndexResponse is the JSON body of the response from POST /_index
+type CreateIndexResponse struct {
+	Result string `json:"result"` // Either IndexCreated or IndexExists
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+}
+
+type listIndexesResponse struct {
+	TotalRows int     `json:"total_rows"`
+	Indexes   []Index `json:"indexes"`
+}
+
+// IndexBuilder defines the available parameter-setting functions.
+type IndexBuilder interface {
+	DefaultField(bool, string) IndexBuilder
+	DesignDoc(string) IndexBuilder
+	Field(string, string) IndexBuilder
+	IndexArrayLengths(bool) IndexBuilder
+	Name(string) IndexBuilder
+	PartialFilterSelector(Selector) IndexBuilder
+	Partitioned(bool) IndexBuilder
+	Type(string) IndexBuilder
+	Build() *indexRequest
+}
+
+type indexField struct {
+	name string
+	kind string
+}
+
+type indexBuilder struct {
+	defaultField          map[string]interface{}
+	designDoc             string
+	fields                []indexField
+	indexArrayLengths     *bool
+	name                  string
+	partialFilterSelector Selector
+	partitioned           *bool
+	indexType             string
+}
+
+// indexRequest is the JSON body of a request to POST /_index.
+type indexRequest struct {
+	Index       IndexDefinition `json:"index"`
+	DesignDoc   string          `json:"ddoc,omitempty"`
+	Name        string          `json:"name,omitempty"`
+	Type        string          `json:"type,omitempty"`
+	Partitioned *bool           `json:"partitioned,omitempty"`
+}
+
+// NewIndex is the entry point.
+func NewIndex() IndexBuilder {
+	return &indexBuilder{indexType: IndexTypeJSON}
+}
+
+// DefaultField configures the default field of a text index, which is
+// searched when a $text selector doesn't name a field.
+func (i *indexBuilder) DefaultField(enabled bool, analyzer string) IndexBuilder {
+	i.defaultField = map[string]interface{}{"enabled": enabled}
+	if analyzer != "" {
+		i.defaultField["analyzer"] = analyzer
+	}
+	return i
+}
+
+func (i *indexBuilder) DesignDoc(ddoc string) IndexBuilder {
+	i.designDoc = ddoc
+	return i
+}
+
+// Field adds a field to the index. For json indexes kind is the sort
+// direction, "asc" or "desc". For text indexes it is the field type,
+// "string", "number" or "boolean".
+func (i *indexBuilder) Field(name, kind string) IndexBuilder {
+	i.fields = append(i.fields, indexField{name, kind})
+	return i
+}
+
+func (i *indexBuilder) IndexArrayLengths(enabled bool) IndexBuilder {
+	i.indexArrayLengths = &enabled
+	return i
+}
+
+func (i *indexBuilder) Name(name string) IndexBuilder {
+	i.name = name
+	return i
+}
+
+func (i *indexBuilder) PartialFilterSelector(selector Selector) IndexBuilder {
+	i.partialFilterSelector = selector
+	return i
+}
+
+func (i *indexBuilder) Partitioned(partitioned bool) IndexBuilder {
+	i.partitioned = &partitioned
+	return i
+}
+
+func (i *indexBuilder) Type(indexType string) IndexBuilder {
+	i.indexType = indexType
+	return i
+}
+
+func (i *indexBuilder) Build() *indexRequest {
+	fields := make([]map[string]interface{}, 0, len(i.fields))
+	for _, field := range i.fields {
+		if i.indexType == IndexTypeText {
+			fields = append(fields, map[string]interface{}{"name": field.name, "type": field.kind})
+		} else {
+			direction := field.kind
+			if direction == "" {
+				direction = "asc"
+			}
+			fields = append(fields, map[string]interface{}{field.name: direction})
+		}
+	}
+
+	req := &indexRequest{
+		Index: IndexDefinition{
+			Fields:                fields,
+			PartialFilterSelector: i.partialFilterSelector,
+			IndexArrayLengths:     i.indexArrayLengths,
+		},
+		DesignDoc:   i.designDoc,
+		Name:        i.name,
+		Type:        i.indexType,
+		Partitioned: i.partitioned,
+	}
+	if i.defaultField != nil {
+		req.Index.DefaultField = i.defaultField
+	}
+
+	return req
+}
+
+// CreateIndex creates a Cloudant Query index. Creating an index that already
+// exists is not an error: the response's Result will be IndexExists rather
+// than IndexCreated.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/cloudant_query.html#creating-an-index
+func (d *Database) CreateIndex(index *indexRequest) (*CreateIndexResponse, error) {
+	body, err := json.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*d.URL, "/_index", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("POST", urlStr, bytes.NewReader(body))
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &CreateIndexResponse{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+
+	return resp, err
+}
+
+// ListIndexes returns all Cloudant Query indexes of the database.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/cloudant_query.html#list-all-cloudant-query-indexes
+func (d *Database) ListIndexes() ([]Index, error) {
+	urlStr, err := Endpoint(*d.URL, "/_index", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("GET", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &listIndexesResponse{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+
+	return resp.Indexes, err
+}
+
+// DeleteIndex deletes a Cloudant Query index of type indexType (IndexTypeJSON
+// or IndexTypeText).
+// See: https://console.bluemix.net/docs/services/Cloudant/api/cloudant_query.html#deleting-an-index
+func (d *Database) DeleteIndex(ddoc, indexType, name string) error {
+	urlStr, err := Endpoint(*d.URL, path.Join("/_index", designDocID(ddoc), indexType, name), nil)
+	if err != nil {
+		return err
+	}
+
+	job, err := d.client.request("DELETE", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return err
+	}
+
+	return expectedReturnCodes(job, 200)
+}
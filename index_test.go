@@ -0,0 +1,113 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestIndex_Args(t *testing.T) {
+	expectedBodyStrings := []string{
+		`"index":{"fields":[{"type":"asc"},{"date":"desc"}],"partial_filter_selector":{"date":{"$exists":true}}}`,
+		`"ddoc":"by-type"`,
+		`"name":"type-date"`,
+		`"type":"json"`,
+		`"partitioned":false`,
+	}
+
+	index := NewIndex().
+		DesignDoc("by-type").
+		Name("type-date").
+		Field("type", "").
+		Field("date", "desc").
+		PartialFilterSelector(Exists("date", true)).
+		Partitioned(false).
+		Build()
+
+	data, _ := json.Marshal(index)
+	body := string(data)
+
+	for _, str := range expectedBodyStrings {
+		if !strings.Contains(body, str) {
+			t.Errorf("parameter encoding not found '%s' in '%s'", str, body)
+			return
+		}
+	}
+}
+
+func TestIndex_TextArgs(t *testing.T) {
+	index := NewIndex().
+		Type(IndexTypeText).
+		Field("name", "string").
+		DefaultField(false, "").
+		IndexArrayLengths(false).
+		Build()
+
+	data, _ := json.Marshal(index)
+	expected := `{"index":{"fields":[{"name":"name","type":"string"}],` +
+		`"default_field":{"enabled":false},"index_array_lengths":false},"type":"text"}`
+
+	if string(data) != expected {
+		t.Errorf("unexpected index encoding '%s'", data)
+	}
+}
+
+func TestDatabase_CreateIndex(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	index := NewIndex().
+		DesignDoc("test").
+		Name("foo-bar").
+		Field("foo", "asc").
+		Field("bar", "asc").
+		Build()
+
+	result, err := database.CreateIndex(index)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if result.Result != IndexCreated || result.ID != "_design/test" || result.Name != "foo-bar" {
+		t.Errorf("unexpected create result %+v", result)
+	}
+
+	result, err = database.CreateIndex(index)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if result.Result != IndexExists {
+		t.Errorf("unexpected create result %+v", result)
+	}
+
+	indexes, err := database.ListIndexes()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	found := false
+	for _, idx := range indexes {
+		if idx.DesignDoc == "_design/test" && idx.Name == "foo-bar" && idx.Type == IndexTypeJSON {
+			found = len(idx.Def.Fields) == 2
+		}
+	}
+	if !found {
+		t.Errorf("index not listed %+v", indexes)
+	}
+
+	err = database.DeleteIndex("test", IndexTypeJSON, "foo-bar")
+	if err != nil {
+		t.Errorf("failed to delete index: %s", err)
+	}
+
+	err = database.DeleteIndex("test", IndexTypeJSON, "foo-bar")
+	if err == nil { // should fail
+		t.Error("unexpected return code from delete")
+	}
+}
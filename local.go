@@ -0,0 +1,91 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/url"
+	"strings"
+)
+
+// localDocURL builds the URL of a local document, given its ID with or
+// without the "_local/" prefix. Unlike Endpoint() the name is escaped as a
+// single path segment, so it may contain slashes.
+func (d *Database) localDocURL(documentID string, params url.Values) string {
+	name := strings.TrimPrefix(documentID, "_local/")
+
+	localURL := *d.URL
+	localURL.Path = strings.TrimRight(localURL.Path, "/") + "/_local/" + name
+	localURL.RawPath = strings.TrimRight(d.URL.EscapedPath(), "/") + "/_local/" + escapePathSegment(name)
+	localURL.RawQuery = params.Encode()
+
+	return localURL.String()
+}
+
+// escapePathSegment escapes s for use as a single segment of a URL path,
+// escaping slashes too. (url.PathEscape does the same, but needs Go 1.8.)
+func escapePathSegment(s string) string {
+	return strings.Replace(url.QueryEscape(s), "+", "%20", -1)
+}
+
+// GetLocal fetches a local document, given its ID with or without the
+// "_local/" prefix. Local documents are not replicated, and are not listed
+// by All() or Changes().
+// See: http://docs.couchdb.org/en/2.1.1/api/local.html
+func (d *Database) GetLocal(documentID string, target interface{}) error {
+	job, err := d.client.request("GET", d.localDocURL(documentID, nil), nil)
+	defer job.Close()
+	if err != nil {
+		return err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return err
+	}
+
+	return json.NewDecoder(job.response.Body).Decode(target)
+}
+
+// PutLocal creates or updates a local document. To update an existing local
+// document its current revision must be set in its _rev attribute.
+func (d *Database) PutLocal(documentID string, document interface{}) (*DocumentMeta, error) {
+	jsonDocument, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("PUT", d.localDocURL(documentID, nil), bytes.NewReader(jsonDocument))
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 201, 202)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DocumentMeta{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+
+	return resp, err
+}
+
+// DeleteLocal deletes a local document with a specified revision.
+func (d *Database) DeleteLocal(documentID, rev string) error {
+	urlStr := d.localDocURL(documentID, url.Values{"rev": []string{rev}})
+
+	job, err := d.client.request("DELETE", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return err
+	}
+
+	return expectedReturnCodes(job, 200, 202)
+}
+
+// LocalDocs returns a channel in which the AllRow types of all local
+// documents can be received. It accepts the same query options as All().
+func (d *Database) LocalDocs(args *allDocsQuery) (<-chan *AllRow, error) {
+	return d.allDocs("/_local_docs", args)
+}
@@ -0,0 +1,87 @@
+package cloudant
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestDatabase_LocalDocURL(t *testing.T) {
+	dbURL, _ := url.Parse("https://account.cloudant.com/my%2Fdb")
+	database := &Database{Name: "my/db", URL: dbURL}
+
+	tests := map[string]string{
+		"checkpoint":        "https://account.cloudant.com/my%2Fdb/_local/checkpoint",
+		"_local/checkpoint": "https://account.cloudant.com/my%2Fdb/_local/checkpoint",
+		"node/1":            "https://account.cloudant.com/my%2Fdb/_local/node%2F1",
+		"_local/a b":        "https://account.cloudant.com/my%2Fdb/_local/a%20b",
+		"a+b?c#d":           "https://account.cloudant.com/my%2Fdb/_local/a%2Bb%3Fc%23d",
+	}
+	for id, expected := range tests {
+		if urlStr := database.localDocURL(id, nil); urlStr != expected {
+			t.Errorf("unexpected URL for %s: %s", id, urlStr)
+		}
+	}
+
+	urlStr := database.localDocURL("checkpoint", url.Values{"rev": []string{"0-1"}})
+	if urlStr != "https://account.cloudant.com/my%2Fdb/_local/checkpoint?rev=0-1" {
+		t.Errorf("unexpected URL %s", urlStr)
+	}
+}
+
+func TestDatabase_LocalDocs(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	meta, err := database.PutLocal("node/1", map[string]interface{}{"seq": "1-abc"})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if meta.ID != "_local/node/1" {
+		t.Errorf("unexpected document meta-data %+v", meta)
+	}
+
+	meta, err = database.PutLocal("_local/node/1", map[string]interface{}{"_rev": meta.Rev, "seq": "2-def"})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	doc := map[string]interface{}{}
+	if err = database.GetLocal("node/1", &doc); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if doc["seq"] != "2-def" {
+		t.Errorf("unexpected local document %v", doc)
+	}
+
+	// Local documents are listed separately from the others
+	if _, err = database.Set(map[string]interface{}{"_id": "doc-1"}); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	rows, err := database.LocalDocs(NewAllDocsQuery().Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	ids := []string{}
+	for row := range rows {
+		ids = append(ids, row.ID)
+	}
+	if len(ids) != 1 || ids[0] != "_local/node/1" {
+		t.Errorf("unexpected local documents %v", ids)
+	}
+
+	if err = database.DeleteLocal("node/1", meta.Rev); err != nil {
+		t.Fatalf("%s", err)
+	}
+	err = database.GetLocal("node/1", &doc)
+	if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 {
+		t.Errorf("expected a 404 error, got %v", err)
+	}
+}
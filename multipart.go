@@ -0,0 +1,426 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"sort"
+)
+
+// AttachmentUpload is an attachment written inline with a document by
+// SetMultipart(). Length must be the exact number of bytes read from Body.
+type AttachmentUpload struct {
+	Name        string
+	ContentType string
+	Length      int64
+	Body        io.Reader
+}
+
+// AttachmentPart is an attachment read from a multipart response. Body is
+// only valid until the next call to NextAttachment() or Next().
+type AttachmentPart struct {
+	Name        string
+	ContentType string
+	Body        io.Reader
+}
+
+// MultipartDocument is a document revision read from a multipart response.
+// Doc holds its JSON body, in which the attachments sent as separate parts
+// have "follows" set rather than base64-encoded data.
+type MultipartDocument struct {
+	Doc     json.RawMessage
+	Missing string // Set instead of Doc for a requested revision that was not found
+	stubs   []attachmentStub
+	reader  *multipart.Reader
+	next    int
+}
+
+// MultipartReader reads the document revisions of a multipart response.
+type MultipartReader struct {
+	job    *Job
+	reader *multipart.Reader
+	mixed  bool
+	done   bool
+}
+
+// attachmentStub is an entry of the _attachments object of a document.
+type attachmentStub struct {
+	Name        string `json:"-"`
+	ContentType string `json:"content_type"`
+	Follows     bool   `json:"follows,omitempty"`
+	Length      int64  `json:"length,omitempty"`
+}
+
+// GetMultipart fetches a document with its attachments as separate parts
+// rather than base64 strings. Attachments are always requested; AttsSince
+// can be used to skip those the caller already has. If OpenRevs is set each
+// requested revision is returned in turn, otherwise a single one is. The
+// response is streamed, and the reader must be closed once done with.
+//
+// Example:
+//
+//	reader, err := db.GetMultipart(docID, NewGetQuery().Build())
+//	defer reader.Close()
+//	doc, err := reader.Next()
+//	for {
+//		att, err := doc.NextAttachment()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+func (d *Database) GetMultipart(documentID string, args *getQuery) (*MultipartReader, error) {
+	params, err := args.GetQuery()
+	if err != nil {
+		return nil, err
+	}
+	params.Set("attachments", "true")
+
+	urlStr, err := Endpoint(*d.URL, documentID, params)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	if len(args.OpenRevs) > 0 {
+		header.Set("Accept", "multipart/mixed")
+	} else {
+		header.Set("Accept", "multipart/related")
+	}
+
+	job, err := d.client.requestWithHeader("GET", urlStr, nil, header)
+	if err != nil {
+		job.Close()
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		job.Close()
+		return nil, err
+	}
+
+	mediaType, mediaParams, err := mime.ParseMediaType(job.response.Header.Get("Content-Type"))
+	if err != nil {
+		job.Close()
+		return nil, err
+	}
+
+	reader := &MultipartReader{job: job}
+	switch mediaType {
+	case "multipart/related", "multipart/mixed":
+		reader.reader = multipart.NewReader(job.response.Body, mediaParams["boundary"])
+		reader.mixed = mediaType == "multipart/mixed"
+	case "application/json":
+		// Documents without attachments can be sent as plain JSON
+	default:
+		job.Close()
+		return nil, fmt.Errorf("unexpected response Content-Type %s", mediaType)
+	}
+
+	return reader, nil
+}
+
+// Next returns the next document revision, or io.EOF once all have been read.
+func (r *MultipartReader) Next() (*MultipartDocument, error) {
+	if r.done {
+		return nil, io.EOF
+	}
+
+	if r.reader == nil {
+		r.done = true
+		return newMultipartDocument(r.job.response.Body, nil)
+	}
+
+	if !r.mixed {
+		r.done = true
+		return readRelated(r.reader)
+	}
+
+	part, err := r.reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+
+	mediaType, mediaParams, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	if mediaType == "multipart/related" {
+		return readRelated(multipart.NewReader(part, mediaParams["boundary"]))
+	}
+
+	return newMultipartDocument(part, nil)
+}
+
+// Close closes the underlying response.
+func (r *MultipartReader) Close() {
+	r.job.Close()
+}
+
+// readRelated reads a document from a multipart/related body, in which the
+// document comes first and is followed by its attachments.
+func readRelated(reader *multipart.Reader) (*MultipartDocument, error) {
+	part, err := reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+
+	return newMultipartDocument(part, reader)
+}
+
+func newMultipartDocument(body io.Reader, reader *multipart.Reader) (*MultipartDocument, error) {
+	doc := &MultipartDocument{reader: reader}
+
+	err := json.NewDecoder(body).Decode(&doc.Doc)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := &struct {
+		Missing string `json:"missing"`
+	}{}
+	if err = json.Unmarshal(doc.Doc, missing); err == nil && missing.Missing != "" {
+		doc.Missing = missing.Missing
+		doc.Doc = nil
+		return doc, nil
+	}
+
+	doc.stubs, err = followingAttachments(doc.Doc)
+
+	return doc, err
+}
+
+// followingAttachments returns the attachment stubs of a document that are
+// sent as separate parts, in the order in which the parts follow.
+func followingAttachments(doc json.RawMessage) ([]attachmentStub, error) {
+	fields := map[string]json.RawMessage{}
+	if err := json.Unmarshal(doc, &fields); err != nil {
+		return nil, err
+	}
+
+	attachments, ok := fields["_attachments"]
+	if !ok {
+		return nil, nil
+	}
+
+	// The object has to be tokenised to keep its keys in order
+	dec := json.NewDecoder(bytes.NewReader(attachments))
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	stubs := []attachmentStub{}
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		stub := attachmentStub{}
+		if err = dec.Decode(&stub); err != nil {
+			return nil, err
+		}
+		if stub.Follows {
+			stub.Name = token.(string)
+			stubs = append(stubs, stub)
+		}
+	}
+
+	return stubs, nil
+}
+
+// Decode unmarshals the JSON body of the document into target.
+func (m *MultipartDocument) Decode(target interface{}) error {
+	return json.Unmarshal(m.Doc, target)
+}
+
+// NextAttachment returns the next attachment part of the document, or io.EOF
+// once all have been read.
+func (m *MultipartDocument) NextAttachment() (*AttachmentPart, error) {
+	if m.reader == nil || m.next >= len(m.stubs) {
+		return nil, io.EOF
+	}
+
+	part, err := m.reader.NextPart()
+	if err != nil {
+		return nil, err
+	}
+
+	stub := m.stubs[m.next]
+	m.next++
+
+	att := &AttachmentPart{
+		Name:        stub.Name,
+		ContentType: stub.ContentType,
+		Body:        part,
+	}
+	if name := part.FileName(); name != "" {
+		att.Name = name
+	}
+	if contentType := part.Header.Get("Content-Type"); contentType != "" {
+		att.ContentType = contentType
+	}
+
+	return att, nil
+}
+
+// SetMultipart writes a document together with its attachments in a single
+// multipart/related request, sending the attachments as is rather than as
+// base64 strings. The document must have an '_id' attribute, and a '_rev' if
+// it already exists. Attachments of the document that are not uploaded are
+// kept if they are listed as stubs in its '_attachments' attribute.
+//
+// The attachments are streamed to the server as they are read, so failed
+// requests are not retried.
+func (d *Database) SetMultipart(document interface{}, attachments []*AttachmentUpload) (*DocumentMeta, error) {
+	jsonDocument, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	// Members are held through pointers, as Go < 1.8 encodes RawMessage
+	// values held by a map as base64 strings.
+	fields := map[string]*json.RawMessage{}
+	if err = json.Unmarshal(jsonDocument, &fields); err != nil {
+		return nil, err
+	}
+
+	documentID := ""
+	if id := fields["_id"]; id == nil || json.Unmarshal(*id, &documentID) != nil || documentID == "" {
+		return nil, fmt.Errorf("document must have an '_id' attribute")
+	}
+
+	// Add the uploads to the document's attachments, marked as following
+	stubs := map[string]*json.RawMessage{}
+	if existing := fields["_attachments"]; existing != nil {
+		if err = json.Unmarshal(*existing, &stubs); err != nil {
+			return nil, err
+		}
+	}
+
+	uploads := map[string]*AttachmentUpload{}
+	for _, att := range attachments {
+		stub, err := json.Marshal(attachmentStub{ContentType: att.ContentType, Follows: true, Length: att.Length})
+		if err != nil {
+			return nil, err
+		}
+		stubs[att.Name] = (*json.RawMessage)(&stub)
+		uploads[att.Name] = att
+	}
+
+	encodedStubs, err := json.Marshal(stubs)
+	if err != nil {
+		return nil, err
+	}
+	fields["_attachments"] = (*json.RawMessage)(&encodedStubs)
+	jsonDocument, err = json.Marshal(fields)
+	if err != nil {
+		return nil, err
+	}
+
+	// The parts must follow in the order of the _attachments object, which
+	// json.Marshal sorts by name.
+	names := make([]string, 0, len(uploads))
+	for name := range uploads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	body, length, contentType, err := multipartBody(jsonDocument, names, uploads)
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*d.URL, documentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	header := http.Header{}
+	header.Set("Content-Type", contentType)
+
+	job, err := d.client.requestStream("PUT", urlStr, &sizedReader{body, length}, header)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 201, 202)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &DocumentMeta{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+
+	return resp, err
+}
+
+// multipartBody lays out a multipart/related body. Only the boundaries and
+// headers are buffered, the attachments are read from their readers while
+// the body is being sent. It returns the body, its length and Content-Type.
+func multipartBody(jsonDocument []byte, names []string, uploads map[string]*AttachmentUpload) (io.Reader, int64, string, error) {
+	buf := &bytes.Buffer{}
+	writer := multipart.NewWriter(buf)
+
+	var segments []io.Reader
+	var length int64
+	flush := func() {
+		segment := make([]byte, buf.Len())
+		copy(segment, buf.Bytes())
+		segments = append(segments, bytes.NewReader(segment))
+		length += int64(len(segment))
+		buf.Reset()
+	}
+
+	header := textproto.MIMEHeader{}
+	header.Set("Content-Type", "application/json")
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, 0, "", err
+	}
+	if _, err = part.Write(jsonDocument); err != nil {
+		return nil, 0, "", err
+	}
+
+	for _, name := range names {
+		att := uploads[name]
+
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", att.ContentType)
+		header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": name}))
+		if _, err = writer.CreatePart(header); err != nil {
+			return nil, 0, "", err
+		}
+
+		flush()
+		segments = append(segments, io.LimitReader(att.Body, att.Length))
+		length += att.Length
+	}
+
+	if err = writer.Close(); err != nil {
+		return nil, 0, "", err
+	}
+	flush()
+
+	contentType := mime.FormatMediaType("multipart/related", map[string]string{"boundary": writer.Boundary()})
+
+	return io.MultiReader(segments...), length, contentType, nil
+}
+
+// sizedReader is a reader of known length, sent with a Content-Length
+// header rather than chunked.
+type sizedReader struct {
+	io.Reader
+	size int64
+}
+
+// Len is used by requestStream to set the Content-Length.
+func (s *sizedReader) Len() int {
+	return int(s.size)
+}
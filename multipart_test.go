@@ -0,0 +1,132 @@
+package cloudant
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/multipart"
+	"strings"
+	"testing"
+)
+
+func TestMultipart_RoundTrip(t *testing.T) {
+	uploads := map[string]*AttachmentUpload{
+		"a.txt": {Name: "a.txt", ContentType: "text/plain", Length: 5, Body: strings.NewReader("hello")},
+		"b.bin": {Name: "b.bin", ContentType: "application/octet-stream", Length: 3, Body: strings.NewReader("\x00\x01\x02")},
+	}
+	doc := []byte(`{"_id":"doc-1","_attachments":{"a.txt":{"content_type":"text/plain","follows":true,"length":5},"b.bin":{"content_type":"application/octet-stream","follows":true,"length":3}}}`)
+
+	body, length, contentType, err := multipartBody(doc, []string{"a.txt", "b.bin"}, uploads)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, _ := ioutil.ReadAll(body)
+	if int64(len(data)) != length {
+		t.Errorf("expected length %d, got %d", length, len(data))
+	}
+
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType != "multipart/related" {
+		t.Fatalf("unexpected Content-Type %s", contentType)
+	}
+
+	mdoc, err := readRelated(multipart.NewReader(strings.NewReader(string(data)), params["boundary"]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(mdoc.Doc) != string(doc) {
+		t.Errorf("unexpected document '%s'", mdoc.Doc)
+	}
+
+	expected := []string{"a.txt:text/plain:hello", "b.bin:application/octet-stream:\x00\x01\x02"}
+	for i := 0; ; i++ {
+		att, err := mdoc.NextAttachment()
+		if err == io.EOF {
+			if i != len(expected) {
+				t.Errorf("unexpected number of attachments %d", i)
+			}
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, _ := ioutil.ReadAll(att.Body)
+		if got := att.Name + ":" + att.ContentType + ":" + string(content); got != expected[i] {
+			t.Errorf("unexpected attachment %q", got)
+		}
+	}
+}
+
+func TestMultipart_FollowingAttachments(t *testing.T) {
+	stubs, err := followingAttachments([]byte(`{"_attachments":{
+		"z.txt": {"content_type": "text/plain", "follows": true},
+		"old.txt": {"content_type": "text/plain", "stub": true},
+		"a.txt": {"content_type": "text/html", "follows": true}
+	}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stubs) != 2 || stubs[0].Name != "z.txt" || stubs[1].Name != "a.txt" {
+		t.Errorf("unexpected attachment order %+v", stubs)
+	}
+}
+
+func TestDatabase_Multipart(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	doc := map[string]interface{}{"_id": "doc-1", "foo": "bar"}
+	meta, err := database.SetMultipart(doc, []*AttachmentUpload{
+		{Name: "a.txt", ContentType: "text/plain", Length: 5, Body: strings.NewReader("hello")},
+		{Name: "b.txt", ContentType: "text/plain", Length: 5, Body: strings.NewReader("world")},
+	})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if meta.ID != "doc-1" || !strings.HasPrefix(meta.Rev, "1-") {
+		t.Errorf("unexpected document meta-data %+v", meta)
+	}
+
+	reader, err := database.GetMultipart("doc-1", NewGetQuery().Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer reader.Close()
+
+	mdoc, err := reader.Next()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	fetched := map[string]interface{}{}
+	if err = mdoc.Decode(&fetched); err != nil || fetched["foo"] != "bar" {
+		t.Errorf("unexpected document %v (%v)", fetched, err)
+	}
+
+	contents := []string{}
+	for {
+		att, err := mdoc.NextAttachment()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("%s", err)
+		}
+		content, _ := ioutil.ReadAll(att.Body)
+		contents = append(contents, att.Name+"="+string(content))
+	}
+	if strings.Join(contents, ",") != "a.txt=hello,b.txt=world" {
+		t.Errorf("unexpected attachments %v", contents)
+	}
+
+	if _, err = reader.Next(); err != io.EOF {
+		t.Errorf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,182 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Partition is a handle on a single partition of a partitioned database.
+// Queries made through it only return documents of that partition.
+// See: https://console.bluemix.net/docs/services/Cloudant/guides/database_partitioning.html
+type Partition struct {
+	Key      string
+	database *Database
+	scoped   *Database // the database, with its URL rooted at the partition
+}
+
+// PartitionInfo represents the partition meta-data
+type PartitionInfo struct {
+	DBName      string `json:"db_name"`
+	Partition   string `json:"partition"`
+	DocCount    int    `json:"doc_count"`
+	DocDelCount int    `json:"doc_del_count"`
+	Sizes       struct {
+		Active   int `json:"active"`
+		External int `json:"external"`
+	} `json:"sizes"`
+}
+
+// ValidatePartitionKey checks that key can be used as a partition key: it
+// must not be empty, "." or "..", contain a colon or a slash, or start with an
+// underscore.
+func ValidatePartitionKey(key string) error {
+	if key == "" {
+		return fmt.Errorf("partition key must not be empty")
+	}
+	if strings.Contains(key, ":") {
+		return fmt.Errorf("partition key %q must not contain ':'", key)
+	}
+	if strings.Contains(key, "/") {
+		return fmt.Errorf("partition key %q must not contain '/'", key)
+	}
+	if key == "." || key == ".." {
+		return fmt.Errorf("partition key %q must not be a dot segment", key)
+	}
+	if strings.HasPrefix(key, "_") {
+		return fmt.Errorf("partition key %q must not start with '_'", key)
+	}
+	return nil
+}
+
+// ValidatePartitionedDocID checks that documentID follows the
+// "{partition}:{docid}" format required by partitioned databases. Design and
+// local documents are not partitioned and are always valid.
+func ValidatePartitionedDocID(documentID string) error {
+	if strings.HasPrefix(documentID, "_design/") || strings.HasPrefix(documentID, "_local/") {
+		return nil
+	}
+
+	parts := strings.SplitN(documentID, ":", 2)
+	if len(parts) != 2 || parts[1] == "" {
+		return fmt.Errorf("document ID %q must have the format {partition}:{docid}", documentID)
+	}
+
+	return ValidatePartitionKey(parts[0])
+}
+
+// Partition returns a handle on the partition with the given key.
+func (d *Database) Partition(key string) (*Partition, error) {
+	if err := ValidatePartitionKey(key); err != nil {
+		return nil, err
+	}
+
+	partitionURL := *d.URL
+	partitionURL.Path += "/_partition/" + key
+
+	return &Partition{
+		Key:      key,
+		database: d,
+		scoped: &Database{
+			client: d.client,
+			Name:   d.Name,
+			URL:    &partitionURL,
+		},
+	}, nil
+}
+
+// DocID returns the ID of the document with the given ID within the partition.
+func (p *Partition) DocID(documentID string) string {
+	return p.Key + ":" + documentID
+}
+
+// checkDocID makes sure that documentID belongs to the partition.
+func (p *Partition) checkDocID(documentID string) error {
+	if err := ValidatePartitionedDocID(documentID); err != nil {
+		return err
+	}
+	if !strings.HasPrefix(documentID, p.Key+":") {
+		return fmt.Errorf("document ID %q is not in partition %q", documentID, p.Key)
+	}
+	return nil
+}
+
+// Info returns partition information.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/partition.html
+func (p *Partition) Info() (*PartitionInfo, error) {
+	job, err := p.database.client.request("GET", p.scoped.URL.String(), nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &PartitionInfo{}
+	err = json.NewDecoder(job.response.Body).Decode(info)
+
+	return info, err
+}
+
+// Get a document of the partition. The document ID must include the
+// partition key, as returned by DocID().
+func (p *Partition) Get(documentID string, args *getQuery, target interface{}) error {
+	if err := p.checkDocID(documentID); err != nil {
+		return err
+	}
+	return p.database.Get(documentID, args, target)
+}
+
+// Set a document of the partition. The document must have an '_id' attribute
+// that includes the partition key, as returned by DocID().
+func (p *Partition) Set(document interface{}) (*DocumentMeta, error) {
+	jsonDocument, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	meta := &struct {
+		ID string `json:"_id"`
+	}{}
+	if err = json.Unmarshal(jsonDocument, meta); err != nil {
+		return nil, err
+	}
+	if err = p.checkDocID(meta.ID); err != nil {
+		return nil, err
+	}
+
+	raw := json.RawMessage(jsonDocument)
+
+	return p.database.Set(&raw) // a pointer, as Go < 1.8 only marshals addressable RawMessages as JSON
+}
+
+// All returns a channel in which the AllRow types of the partition's
+// documents can be received.
+func (p *Partition) All(args *allDocsQuery) (<-chan *AllRow, error) {
+	return p.scoped.All(args)
+}
+
+// View returns a channel in which the ViewRow types of a partitioned view
+// can be received.
+func (p *Partition) View(ddoc, view string, args *viewQuery) (<-chan *ViewRow, error) {
+	return p.scoped.View(ddoc, view, args)
+}
+
+// Find queries the partition with a Cloudant Query selector.
+func (p *Partition) Find(args *findQuery) (*FindResponse, error) {
+	return p.scoped.Find(args)
+}
+
+// FindPager returns a FindPager that follows _find bookmarks within the
+// partition.
+func (p *Partition) FindPager(args *findQuery) *FindPager {
+	return NewFindPager(p.scoped, args)
+}
+
+// Search queries a partitioned Cloudant Search index.
+func (p *Partition) Search(ddoc, index string, args *searchQuery) (*SearchResponse, error) {
+	return p.scoped.Search(ddoc, index, args)
+}
@@ -0,0 +1,137 @@
+package cloudant
+
+import (
+	"fmt"
+	"net/url"
+	"testing"
+)
+
+func TestPartition_ValidateDocID(t *testing.T) {
+	valid := []string{"sensor-1:reading-1", "sensor-1:reading:1", "_design/test", "_local/checkpoint"}
+	for _, id := range valid {
+		if err := ValidatePartitionedDocID(id); err != nil {
+			t.Errorf("unexpected error for %s: %s", id, err)
+		}
+	}
+
+	invalid := []string{"", "reading-1", "sensor-1:", ":reading-1", "_sensor:reading-1", "sensor/1:reading-1"}
+	for _, id := range invalid {
+		if err := ValidatePartitionedDocID(id); err == nil {
+			t.Errorf("expected an error for %q", id)
+		}
+	}
+}
+
+func TestPartition_Scope(t *testing.T) {
+	databaseURL, _ := url.Parse("https://user123.cloudant.com/mydb")
+	database := &Database{client: &CouchClient{}, Name: "mydb", URL: databaseURL}
+
+	for _, key := range []string{"bad:key", "bad/key", "../_all_docs", ".", ".."} {
+		if _, err := database.Partition(key); err == nil {
+			t.Errorf("expected an error for the invalid partition key %q", key)
+		}
+	}
+
+	partition, err := database.Partition("sensor-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if partition.scoped.URL.String() != "https://user123.cloudant.com/mydb/_partition/sensor-1" {
+		t.Errorf("unexpected partition URL %s", partition.scoped.URL)
+	}
+	if partition.DocID("reading-1") != "sensor-1:reading-1" {
+		t.Errorf("unexpected document ID %s", partition.DocID("reading-1"))
+	}
+	if err = partition.checkDocID("sensor-2:reading-1"); err == nil {
+		t.Error("expected an error for a document of another partition")
+	}
+	if _, err = partition.Set(cloudantDocument{ID: "reading-1"}); err == nil {
+		t.Error("expected an error for an unpartitioned document ID")
+	}
+}
+
+func TestDatabase_Partition(t *testing.T) {
+	if travis() {
+		fmt.Printf("[SKIP] TestDatabase_Partition requires CouchDB 3.X or Cloudant")
+		return
+	}
+	client, err := makeClient()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	testdbname, err := dbName()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	database, err := client.GetOrCreatePartitioned(testdbname)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		client.Delete(database.Name)
+	}()
+
+	info, err := database.Info()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !info.Props.Partitioned {
+		t.Error("expected a partitioned database")
+	}
+
+	uploader := database.Bulk(100, -1, 0)
+	for i := 0; i < 100; i++ {
+		uploader.Upload(cloudantDocument{
+			ID:  fmt.Sprintf("p%d:doc-%.3d", i%2, i),
+			Foo: "foobar",
+			Bar: i % 2,
+		})
+	}
+	uploader.Flush()
+
+	partition, err := database.Partition("p1")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	partitionInfo, err := partition.Info()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if partitionInfo.DocCount != 50 || partitionInfo.Partition != "p1" {
+		t.Errorf("unexpected partition info %+v", partitionInfo)
+	}
+
+	rows, err := partition.All(NewAllDocsQuery().Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	i := 0
+	for range rows {
+		i++
+	}
+	if 50 != i {
+		t.Errorf("unexpected number of rows received %d", i)
+	}
+
+	response, err := partition.Find(NewFindQuery().Selector(Eq("bar", 1)).Limit(100).Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	i = 0
+	for range response.Docs {
+		i++
+	}
+	if 50 != i {
+		t.Errorf("unexpected number of docs received %d", i)
+	}
+
+	doc := &cloudantDocument{}
+	if err = partition.Get(partition.DocID("doc-001"), &getQuery{}, doc); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if doc.ID != "p1:doc-001" {
+		t.Errorf("unexpected document %s", doc.ID)
+	}
+}
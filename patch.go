@@ -0,0 +1,397 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// MergePatch is an RFC 7396 JSON Merge Patch: its fields are set on the
+// document, nested objects are merged, and null values remove fields.
+// See: https://tools.ietf.org/html/rfc7396
+type MergePatch map[string]interface{}
+
+// JSONPatch is an RFC 6902 JSON Patch: a list of operations applied in turn.
+// See: https://tools.ietf.org/html/rfc6902
+type JSONPatch []PatchOperation
+
+// PatchOperation is a single JSON Patch operation. Op is one of "add",
+// "remove", "replace", "move", "copy" or "test".
+type PatchOperation struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value"` // Written for all but "remove", "move" and "copy", even if nil
+}
+
+type patchOperationJSON struct {
+	Op    string       `json:"op"`
+	Path  string       `json:"path"`
+	From  string       `json:"from,omitempty"`
+	Value *interface{} `json:"value,omitempty"`
+}
+
+// MarshalJSON implements the json.Marshaler interface. A nil Value is written
+// as null, since "add", "replace" and "test" operations require a value.
+func (op PatchOperation) MarshalJSON() ([]byte, error) {
+	operation := patchOperationJSON{Op: op.Op, Path: op.Path, From: op.From}
+	switch op.Op {
+	case "remove", "move", "copy":
+	default:
+		operation.Value = &op.Value
+	}
+
+	return json.Marshal(operation)
+}
+
+// PatchTestError is returned by Patch() if a JSON Patch "test" operation
+// fails. No changes are made to the document.
+type PatchTestError struct {
+	Path     string
+	Expected interface{}
+	Actual   interface{}
+}
+
+// Error implements the error interface
+func (e *PatchTestError) Error() string {
+	expected, _ := json.Marshal(e.Expected)
+	actual, _ := json.Marshal(e.Actual)
+	return fmt.Sprintf("patch test failed at %q: expected %s, got %s", e.Path, expected, actual)
+}
+
+// Patch applies a patch to the current revision of a document and saves the
+// result, retrying on conflict as Update() does. The patch is either a
+// MergePatch or a JSONPatch, or its JSON encoding as a json.RawMessage or
+// []byte, in which case an array is taken to be a JSON Patch and an object
+// a merge patch.
+func (d *Database) Patch(documentID string, patch interface{}) (*DocumentMeta, error) {
+	apply, err := patchFunc(patch)
+	if err != nil {
+		return nil, err
+	}
+
+	doc := map[string]interface{}{}
+	return d.Update(documentID, &doc, func() error {
+		patched, err := apply(doc)
+		if err != nil {
+			return err
+		}
+
+		object, ok := patched.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("patched document is not a JSON object")
+		}
+		doc = object
+
+		return nil
+	})
+}
+
+// patchFunc returns a function applying patch to a document.
+func patchFunc(patch interface{}) (func(interface{}) (interface{}, error), error) {
+	switch p := patch.(type) {
+	case json.RawMessage:
+		return patchFunc([]byte(p))
+	case []byte:
+		if trimmed := bytes.TrimSpace(p); len(trimmed) > 0 && trimmed[0] == '[' {
+			ops := JSONPatch{}
+			if err := json.Unmarshal(p, &ops); err != nil {
+				return nil, err
+			}
+			return patchFunc(ops)
+		}
+		merge := MergePatch{}
+		if err := json.Unmarshal(p, &merge); err != nil {
+			return nil, err
+		}
+		return patchFunc(merge)
+	case MergePatch:
+		normalised, err := normaliseJSON(map[string]interface{}(p))
+		if err != nil {
+			return nil, err
+		}
+		return func(doc interface{}) (interface{}, error) {
+			return applyMergePatch(doc, normalised), nil
+		}, nil
+	case JSONPatch:
+		ops := make(JSONPatch, len(p))
+		for i, op := range p {
+			value, err := normaliseJSON(op.Value)
+			if err != nil {
+				return nil, err
+			}
+			op.Value = value
+			ops[i] = op
+		}
+		return func(doc interface{}) (interface{}, error) {
+			return applyJSONPatch(doc, ops)
+		}, nil
+	}
+
+	return nil, fmt.Errorf("unsupported patch type %T", patch)
+}
+
+// normaliseJSON round-trips a value through JSON, so that it compares equal
+// to values decoded from documents.
+func normaliseJSON(value interface{}) (interface{}, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	var normalised interface{}
+	err = json.Unmarshal(data, &normalised)
+
+	return normalised, err
+}
+
+// applyMergePatch implements the RFC 7396 MergePatch algorithm.
+func applyMergePatch(target, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+		} else {
+			targetObject[key] = applyMergePatch(targetObject[key], value)
+		}
+	}
+
+	return targetObject
+}
+
+// applyJSONPatch applies JSON Patch operations to a document, returning the
+// patched document.
+func applyJSONPatch(doc interface{}, ops JSONPatch) (interface{}, error) {
+	for _, op := range ops {
+		path, err := parsePointer(op.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		// Values are copied, so that later operations modifying them don't
+		// change the patch itself.
+		switch op.Op {
+		case "add":
+			var value interface{}
+			if value, err = normaliseJSON(op.Value); err == nil {
+				doc, err = pointerAdd(doc, path, value)
+			}
+		case "remove":
+			doc, err = pointerRemove(doc, path)
+		case "replace":
+			var value interface{}
+			if value, err = normaliseJSON(op.Value); err != nil {
+				break
+			}
+			if _, err = pointerGet(doc, path); err == nil {
+				doc, err = pointerSet(doc, path, value)
+			}
+		case "move", "copy":
+			var from []string
+			if from, err = parsePointer(op.From); err != nil {
+				return nil, err
+			}
+			var value interface{}
+			if value, err = pointerGet(doc, from); err != nil {
+				break
+			}
+			if op.Op == "copy" {
+				value, err = normaliseJSON(value) // deep copy
+			} else if strings.HasPrefix(op.Path+"/", op.From+"/") && op.Path != op.From {
+				err = fmt.Errorf("can't move %q into one of its children", op.From)
+			} else {
+				doc, err = pointerRemove(doc, from)
+			}
+			if err == nil {
+				doc, err = pointerAdd(doc, path, value)
+			}
+		case "test":
+			var value interface{}
+			if value, err = pointerGet(doc, path); err == nil && !reflect.DeepEqual(value, op.Value) {
+				err = &PatchTestError{Path: op.Path, Expected: op.Value, Actual: value}
+			}
+		default:
+			err = fmt.Errorf("unknown patch operation %q", op.Op)
+		}
+
+		if err != nil {
+			if _, ok := err.(*PatchTestError); ok {
+				return nil, err
+			}
+			return nil, fmt.Errorf("patch %s %q failed: %s", op.Op, op.Path, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// parsePointer splits an RFC 6901 JSON Pointer into its reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return []string{}, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, fmt.Errorf("invalid JSON pointer %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+
+	return tokens, nil
+}
+
+// arrayIndex parses the index of an array element. If end is true "-" is
+// accepted and refers to the position after the last element.
+func arrayIndex(token string, array []interface{}, end bool) (int, error) {
+	if end && token == "-" {
+		return len(array), nil
+	}
+
+	index, err := strconv.Atoi(token)
+	if err != nil || index < 0 || (token != "0" && strings.HasPrefix(token, "0")) {
+		return 0, fmt.Errorf("invalid array index %q", token)
+	}
+
+	max := len(array) - 1
+	if end {
+		max = len(array)
+	}
+	if index > max {
+		return 0, fmt.Errorf("array index %d out of bounds", index)
+	}
+
+	return index, nil
+}
+
+// pointerGet returns the value at path.
+func pointerGet(doc interface{}, path []string) (interface{}, error) {
+	for _, token := range path {
+		switch node := doc.(type) {
+		case map[string]interface{}:
+			value, ok := node[token]
+			if !ok {
+				return nil, fmt.Errorf("member %q not found", token)
+			}
+			doc = value
+		case []interface{}:
+			index, err := arrayIndex(token, node, false)
+			if err != nil {
+				return nil, err
+			}
+			doc = node[index]
+		default:
+			return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+		}
+	}
+
+	return doc, nil
+}
+
+// pointerSet replaces the value at path, which must exist if it is an array
+// element. It returns the updated document.
+func pointerSet(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parent, err := pointerGet(doc, path[:len(path)-1])
+	if err != nil {
+		return nil, err
+	}
+
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		node[token] = value
+	case []interface{}:
+		index, err := arrayIndex(token, node, false)
+		if err != nil {
+			return nil, err
+		}
+		node[index] = value
+	default:
+		return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+	}
+
+	return doc, nil
+}
+
+// pointerAdd adds a value at path, inserting it if path is an array index.
+// It returns the updated document.
+func pointerAdd(doc interface{}, path []string, value interface{}) (interface{}, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	parentPath := path[:len(path)-1]
+	parent, err := pointerGet(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	token := path[len(path)-1]
+	array, ok := parent.([]interface{})
+	if !ok {
+		return pointerSet(doc, path, value)
+	}
+
+	index, err := arrayIndex(token, array, true)
+	if err != nil {
+		return nil, err
+	}
+
+	inserted := make([]interface{}, 0, len(array)+1)
+	inserted = append(inserted, array[:index]...)
+	inserted = append(inserted, value)
+	inserted = append(inserted, array[index:]...)
+
+	return pointerSet(doc, parentPath, inserted)
+}
+
+// pointerRemove removes the value at path, returning the updated document.
+func pointerRemove(doc interface{}, path []string) (interface{}, error) {
+	if len(path) == 0 {
+		return nil, fmt.Errorf("can't remove the whole document")
+	}
+
+	parentPath := path[:len(path)-1]
+	parent, err := pointerGet(doc, parentPath)
+	if err != nil {
+		return nil, err
+	}
+
+	token := path[len(path)-1]
+	switch node := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := node[token]; !ok {
+			return nil, fmt.Errorf("member %q not found", token)
+		}
+		delete(node, token)
+		return doc, nil
+	case []interface{}:
+		index, err := arrayIndex(token, node, false)
+		if err != nil {
+			return nil, err
+		}
+		removed := make([]interface{}, 0, len(node)-1)
+		removed = append(removed, node[:index]...)
+		removed = append(removed, node[index+1:]...)
+		return pointerSet(doc, parentPath, removed)
+	}
+
+	return nil, fmt.Errorf("can't reference %q in a scalar value", token)
+}
@@ -0,0 +1,183 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func applyTestPatch(t *testing.T, doc string, patch interface{}) (string, error) {
+	var target interface{}
+	if err := json.Unmarshal([]byte(doc), &target); err != nil {
+		t.Fatal(err)
+	}
+
+	apply, err := patchFunc(patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	patched, err := apply(target)
+	if err != nil {
+		return "", err
+	}
+
+	data, _ := json.Marshal(patched)
+	return string(data), nil
+}
+
+func TestPatch_MergePatch(t *testing.T) {
+	// From RFC 7396, appendix A
+	doc := `{"title": "Goodbye!", "author": {"givenName": "John", "familyName": "Doe"}, "tags": ["example", "sample"], "content": "This will be unchanged"}`
+	patch := []byte(`{"title": "Hello!", "phoneNumber": "+01-123-456-7890", "author": {"familyName": null}, "tags": ["example"]}`)
+
+	patched, err := applyTestPatch(t, doc, patch)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `{"author":{"givenName":"John"},"content":"This will be unchanged","phoneNumber":"+01-123-456-7890","tags":["example"],"title":"Hello!"}`
+	if patched != expected {
+		t.Errorf("unexpected result %s", patched)
+	}
+
+	patched, _ = applyTestPatch(t, `{"a": {"b": 1}}`, MergePatch{"a": map[string]interface{}{"c": 2}})
+	if patched != `{"a":{"b":1,"c":2}}` {
+		t.Errorf("unexpected result %s", patched)
+	}
+}
+
+func TestPatch_JSONPatch(t *testing.T) {
+	tests := []struct {
+		doc      string
+		patch    string
+		expected string
+	}{
+		{`{"foo": "bar"}`, `[{"op": "add", "path": "/baz", "value": "qux"}]`, `{"baz":"qux","foo":"bar"}`},
+		{`{"foo": ["bar", "baz"]}`, `[{"op": "add", "path": "/foo/1", "value": "qux"}]`, `{"foo":["bar","qux","baz"]}`},
+		{`{"foo": ["bar"]}`, `[{"op": "add", "path": "/foo/-", "value": ["abc"]}]`, `{"foo":["bar",["abc"]]}`},
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "remove", "path": "/baz"}]`, `{"foo":"bar"}`},
+		{`{"foo": ["bar", "qux", "baz"]}`, `[{"op": "remove", "path": "/foo/1"}]`, `{"foo":["bar","baz"]}`},
+		{`{"baz": "qux", "foo": "bar"}`, `[{"op": "replace", "path": "/baz", "value": "boo"}]`, `{"baz":"boo","foo":"bar"}`},
+		{`{"foo": {"bar": "baz", "waldo": "fred"}, "qux": {"corge": "grault"}}`, `[{"op": "move", "from": "/foo/waldo", "path": "/qux/thud"}]`, `{"foo":{"bar":"baz"},"qux":{"corge":"grault","thud":"fred"}}`},
+		{`{"foo": ["all", "grass", "cows", "eat"]}`, `[{"op": "move", "from": "/foo/1", "path": "/foo/3"}]`, `{"foo":["all","cows","eat","grass"]}`},
+		{`{"a": {"b": [1]}}`, `[{"op": "copy", "from": "/a", "path": "/c"}, {"op": "add", "path": "/c/b/-", "value": 2}]`, `{"a":{"b":[1]},"c":{"b":[1,2]}}`},
+		{`{"a/b": 1, "m~n": 2}`, `[{"op": "test", "path": "/a~1b", "value": 1}, {"op": "replace", "path": "/m~0n", "value": 3}]`, `{"a/b":1,"m~n":3}`},
+		{`{"baz": "qux", "foo": ["a", 2, "c"]}`, `[{"op": "test", "path": "/baz", "value": "qux"}, {"op": "test", "path": "/foo/1", "value": 2}]`, `{"baz":"qux","foo":["a",2,"c"]}`},
+	}
+
+	for i, test := range tests {
+		patched, err := applyTestPatch(t, test.doc, json.RawMessage(test.patch))
+		if err != nil {
+			t.Errorf("%d: %s", i, err)
+			continue
+		}
+		if patched != test.expected {
+			t.Errorf("%d: unexpected result %s", i, patched)
+		}
+	}
+
+	errors := []string{
+		`[{"op": "add", "path": "/baz/bat", "value": "qux"}]`,
+		`[{"op": "remove", "path": "/missing"}]`,
+		`[{"op": "replace", "path": "/missing", "value": 1}]`,
+		`[{"op": "move", "from": "/foo", "path": "/foo/child"}]`,
+		`[{"op": "add", "path": "/foo/bar", "value": 1}]`,
+		`[{"op": "frobnicate", "path": "/foo"}]`,
+	}
+	for _, patch := range errors {
+		if _, err := applyTestPatch(t, `{"foo": "bar"}`, json.RawMessage(patch)); err == nil {
+			t.Errorf("expected an error applying %s", patch)
+		}
+	}
+}
+
+func TestPatchOperation_MarshalJSON(t *testing.T) {
+	patch := JSONPatch{
+		{Op: "add", Path: "/a", Value: nil},
+		{Op: "replace", Path: "/b", Value: 1},
+		{Op: "remove", Path: "/c"},
+		{Op: "move", From: "/d", Path: "/e"},
+	}
+
+	data, err := json.Marshal(patch)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	expected := `[{"op":"add","path":"/a","value":null},{"op":"replace","path":"/b","value":1},` +
+		`{"op":"remove","path":"/c"},{"op":"move","path":"/e","from":"/d"}]`
+	if string(data) != expected {
+		t.Errorf("unexpected JSON Patch encoding %s", data)
+	}
+}
+
+func TestPatch_TestFailure(t *testing.T) {
+	patch := JSONPatch{
+		{Op: "test", Path: "/status", Value: "draft"},
+		{Op: "replace", Path: "/status", Value: "published"},
+	}
+
+	_, err := applyTestPatch(t, `{"status": "archived"}`, patch)
+	testErr, ok := err.(*PatchTestError)
+	if !ok {
+		t.Fatalf("expected a PatchTestError, got %v", err)
+	}
+	if testErr.Path != "/status" || !reflect.DeepEqual(testErr.Actual, "archived") {
+		t.Errorf("unexpected error %+v", testErr)
+	}
+	if !strings.Contains(testErr.Error(), `expected "draft", got "archived"`) {
+		t.Errorf("unexpected error message %s", testErr)
+	}
+}
+
+func TestDatabase_Patch(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	_, err = database.Set(map[string]interface{}{"_id": "doc-1", "status": "draft", "tags": []string{"a"}})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	meta, err := database.Patch("doc-1", MergePatch{"status": "published"})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !strings.HasPrefix(meta.Rev, "2-") {
+		t.Errorf("unexpected revision %s", meta.Rev)
+	}
+
+	_, err = database.Patch("doc-1", JSONPatch{
+		{Op: "test", Path: "/status", Value: "draft"},
+		{Op: "add", Path: "/tags/-", Value: "b"},
+	})
+	if _, ok := err.(*PatchTestError); !ok {
+		t.Errorf("expected a PatchTestError, got %v", err)
+	}
+
+	meta, err = database.Patch("doc-1", JSONPatch{
+		{Op: "test", Path: "/status", Value: "published"},
+		{Op: "add", Path: "/tags/-", Value: "b"},
+	})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !strings.HasPrefix(meta.Rev, "3-") {
+		t.Errorf("unexpected revision %s", meta.Rev)
+	}
+
+	doc := map[string]interface{}{}
+	database.Get("doc-1", NewGetQuery().Build(), &doc)
+	if doc["status"] != "published" || !reflect.DeepEqual(doc["tags"], []interface{}{"a", "b"}) {
+		t.Errorf("unexpected document %v", doc)
+	}
+}
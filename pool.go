@@ -9,6 +9,7 @@ import (
 	"math/rand"
 	"net/http"
 	"runtime"
+	"strings"
 	"time"
 )
 
@@ -29,6 +30,8 @@ type Job struct {
 	request    *http.Request
 	response   *http.Response
 	bodyBytes  []byte
+	stream     io.Reader // request body sent without buffering, see requestStream
+	streamPos  int64     // offset to rewind a seekable stream to before a retry
 	retryCount int
 	error      error
 	isDone     chan bool
@@ -65,9 +68,10 @@ func CreateJob(request *http.Request) *Job {
 	return job
 }
 
-// Close closes the response body reader to prevent a memory leak, even if not used
+// Close closes the response body reader to prevent a memory leak, even if not used.
+// It may be called on a nil job, as returned by requests that failed to start.
 func (j *Job) Close() {
-	if j.response != nil {
+	if j != nil && j.response != nil {
 		io.Copy(ioutil.Discard, j.response.Body)
 		j.response.Body.Close()
 	}
@@ -78,6 +82,16 @@ func (j *Job) Response() *http.Response {
 	return j.response
 }
 
+// rewind moves a streamed request body back to where it started.
+func (j *Job) rewind() error {
+	seeker, ok := j.stream.(io.Seeker)
+	if !ok {
+		return fmt.Errorf("request body can't be rewound for a retry")
+	}
+	_, err := seeker.Seek(j.streamPos, 0) // io.SeekStart, which needs Go 1.7
+	return err
+}
+
 // Mark job as done.
 func (j *Job) done() { j.isDone <- true }
 
@@ -118,20 +132,42 @@ func (w *worker) start() {
 			LogFunc("Request (attempt: %d) %s %s", job.retryCount, job.request.Method,
 				job.request.URL.String())
 
-			// save body for retries
-			if job.retryCount == 0 && job.request.Body != nil {
-				var err error
-				job.bodyBytes, err = ioutil.ReadAll(job.request.Body)
-				if err != nil {
-					LogFunc("failed to read request body, %s", err)
+			if job.stream != nil {
+				// streamed bodies aren't buffered, they are rewound for retries
+				if job.retryCount > 0 {
+					if err := job.rewind(); err != nil {
+						job.error = err
+						job.done()
+						return
+					}
+				}
+				job.request.Body = ioutil.NopCloser(job.stream)
+			} else {
+				// save body for retries
+				if job.retryCount == 0 && job.request.Body != nil {
+					var err error
+					job.bodyBytes, err = ioutil.ReadAll(job.request.Body)
+					if err != nil {
+						LogFunc("failed to read request body, %s", err)
+					}
 				}
-			}
 
-			job.request.Body = ioutil.NopCloser(bytes.NewReader(job.bodyBytes))
+				if len(job.bodyBytes) > 0 {
+					job.request.Body = ioutil.NopCloser(bytes.NewReader(job.bodyBytes))
+				} else {
+					job.request.Body = nil // sent with no body, rather than a chunked empty one
+				}
+			}
 
 			// add go-cloudant UA
 			job.request.Header.Add("User-Agent", "go-cloudant/"+VERSION+"/"+runtime.Version())
 
+			// add the current IAM token, which may have been renewed since the
+			// last attempt
+			if worker.client.iam != nil && !job.isLogin {
+				job.request.Header.Set("Authorization", "Bearer "+worker.client.iam.token())
+			}
+
 			resp, err := worker.client.httpClient.Do(job.request)
 
 			var retry bool
@@ -141,11 +177,18 @@ func (w *worker) start() {
 			} else {
 				switch resp.StatusCode {
 				case 401:
-					if !job.isLogin {
+					if job.isLogin {
+						break
+					}
+					if worker.client.iam != nil {
+						LogFunc("renewing IAM token")
+						sent := strings.TrimPrefix(job.request.Header.Get("Authorization"), "Bearer ")
+						worker.client.iam.renew(sent)
+					} else {
 						LogFunc("renewing session")
-						w.client.LogIn()
-						retry = true
+						worker.client.LogIn()
 					}
+					retry = true
 				case 403:
 					response := &CredentialsExpiredResponse{}
 					err = json.NewDecoder(resp.Body).Decode(response)
@@ -153,7 +196,7 @@ func (w *worker) start() {
 					retry = false
 					if err == nil && response.Error == "credentials_expired" {
 						LogFunc("renewing session")
-						w.client.LogIn()
+						worker.client.LogIn()
 						retry = true
 					}
 				case 429:
@@ -165,14 +208,20 @@ func (w *worker) start() {
 				}
 			}
 
+			if retry && job.stream != nil {
+				if _, ok := job.stream.(io.Seeker); !ok {
+					retry = false // the body has been consumed and can't be resent
+				}
+			}
+
 			if retry {
-				if job.retryCount < w.client.retryCountMax {
+				if job.retryCount < worker.client.retryCountMax {
 					job.retryCount += 1
 
 					go func(startDelay int) {
 						time.Sleep(time.Duration(startDelay) * time.Second)
-						w.client.Execute(job)
-					}(random(w.client.retryDelayMin, w.client.retryDelayMax))
+						worker.client.Execute(job)
+					}(random(worker.client.retryDelayMin, worker.client.retryDelayMax))
 
 					return
 				} else {
@@ -0,0 +1,200 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+)
+
+// Limits of a single _purge request, matching CouchDB's default
+// max_document_id_number and max_revisions_number purge settings
+var purgeBatchDocs = 100
+var purgeBatchRevs = 1000
+
+// PurgeResponse is the JSON body of the response from the _purge endpoint
+type PurgeResponse struct {
+	PurgeSeq interface{}         `json:"purge_seq"`
+	Purged   map[string][]string `json:"purged"`
+}
+
+// Purge permanently removes document revisions from the database, leaving
+// no tombstone behind. It takes a map of document IDs to the revisions to
+// purge, and returns the revisions that were actually purged. Large purges
+// are split into several requests.
+// See: http://docs.couchdb.org/en/2.3.0/api/database/misc.html#db-purge
+func (d *Database) Purge(revs map[string][]string) (map[string][]string, error) {
+	urlStr, err := Endpoint(*d.URL, "/_purge", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	purged := map[string][]string{}
+
+	for _, batch := range purgeBatches(revs, purgeBatchDocs, purgeBatchRevs) {
+		body, err := json.Marshal(batch)
+		if err != nil {
+			return purged, err
+		}
+
+		job, err := d.client.request("POST", urlStr, bytes.NewReader(body))
+		if err != nil {
+			job.Close()
+			return purged, err
+		}
+
+		err = expectedReturnCodes(job, 201, 202)
+		if err != nil {
+			job.Close()
+			return purged, err
+		}
+
+		resp := &PurgeResponse{}
+		err = json.NewDecoder(job.response.Body).Decode(resp)
+		job.Close()
+		if err != nil {
+			return purged, err
+		}
+
+		for documentID, purgedRevs := range resp.Purged {
+			if len(purgedRevs) > 0 {
+				purged[documentID] = append(purged[documentID], purgedRevs...)
+			}
+		}
+	}
+
+	return purged, nil
+}
+
+// purgeBatches splits a purge request into batches of at most maxDocs
+// documents and maxRevs revisions. The revisions of a document with more
+// than maxRevs of them are spread over several batches.
+func purgeBatches(revs map[string][]string, maxDocs, maxRevs int) []map[string][]string {
+	ids := make([]string, 0, len(revs))
+	for id := range revs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	batches := []map[string][]string{}
+	batch := map[string][]string{}
+	batchRevs := 0
+
+	for _, id := range ids {
+		remaining := revs[id]
+		for {
+			if len(batch) >= maxDocs || (batchRevs >= maxRevs && len(batch) > 0) {
+				batches = append(batches, batch)
+				batch = map[string][]string{}
+				batchRevs = 0
+			}
+
+			n := len(remaining)
+			if n > maxRevs-batchRevs {
+				n = maxRevs - batchRevs
+			}
+			batch[id] = remaining[:n]
+			batchRevs += n
+			remaining = remaining[n:]
+
+			if len(remaining) == 0 {
+				break
+			}
+		}
+	}
+
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches
+}
+
+// PurgeAll permanently removes every leaf revision of a document, including
+// deleted and conflicting ones, and returns the revisions purged.
+func (d *Database) PurgeAll(documentID string) ([]string, error) {
+	leaves, err := d.openRevs(documentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	revs := []string{}
+	for _, leaf := range leaves {
+		if rev, ok := leaf["_rev"].(string); ok {
+			revs = append(revs, rev)
+		}
+	}
+	if len(revs) == 0 {
+		return revs, nil
+	}
+
+	purged, err := d.Purge(map[string][]string{documentID: revs})
+
+	return purged[documentID], err
+}
+
+// PurgedInfosLimit returns the number of purges the database remembers,
+// which limits how far behind replicas and indexes can fall and still apply
+// them.
+func (d *Database) PurgedInfosLimit() (int, error) {
+	return d.getLimit("/_purged_infos_limit")
+}
+
+// SetPurgedInfosLimit sets the number of purges the database remembers.
+func (d *Database) SetPurgedInfosLimit(limit int) error {
+	return d.setLimit("/_purged_infos_limit", limit)
+}
+
+// RevsLimit returns the number of revisions of each document the database
+// keeps track of.
+func (d *Database) RevsLimit() (int, error) {
+	return d.getLimit("/_revs_limit")
+}
+
+// SetRevsLimit sets the number of revisions of each document the database
+// keeps track of.
+func (d *Database) SetRevsLimit(limit int) error {
+	return d.setLimit("/_revs_limit", limit)
+}
+
+// getLimit reads a database setting holding a number.
+func (d *Database) getLimit(pathStr string) (int, error) {
+	urlStr, err := Endpoint(*d.URL, pathStr, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	job, err := d.client.request("GET", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return 0, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return 0, err
+	}
+
+	limit := 0
+	err = json.NewDecoder(job.response.Body).Decode(&limit)
+
+	return limit, err
+}
+
+// setLimit writes a database setting holding a number.
+func (d *Database) setLimit(pathStr string, limit int) error {
+	urlStr, err := Endpoint(*d.URL, pathStr, nil)
+	if err != nil {
+		return err
+	}
+
+	body := bytes.NewReader([]byte(strconv.Itoa(limit)))
+
+	job, err := d.client.request("PUT", urlStr, body)
+	defer job.Close()
+	if err != nil {
+		return err
+	}
+
+	return expectedReturnCodes(job, 200)
+}
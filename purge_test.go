@@ -0,0 +1,109 @@
+package cloudant
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestPurgeBatches(t *testing.T) {
+	revs := map[string][]string{
+		"doc-c": {"1-c"},
+		"doc-a": {"1-a", "2-a"},
+		"doc-b": {"1-b"},
+	}
+
+	batches := purgeBatches(revs, 2, 10)
+	if len(batches) != 2 {
+		t.Fatalf("unexpected number of batches %d", len(batches))
+	}
+	if len(batches[0]) != 2 || len(batches[0]["doc-a"]) != 2 || batches[0]["doc-b"][0] != "1-b" {
+		t.Errorf("unexpected first batch %v", batches[0])
+	}
+	if len(batches[1]) != 1 || batches[1]["doc-c"][0] != "1-c" {
+		t.Errorf("unexpected second batch %v", batches[1])
+	}
+
+	if batches = purgeBatches(map[string][]string{}, 2, 10); len(batches) != 0 {
+		t.Errorf("unexpected batches for an empty purge %v", batches)
+	}
+}
+
+func TestPurgeBatches_Revisions(t *testing.T) {
+	revs := map[string][]string{
+		"doc-a": {"1-a", "2-a", "3-a", "4-a", "5-a"},
+		"doc-b": {"1-b"},
+	}
+
+	// at most 2 revisions per batch: doc-a is spread over three batches
+	batches := purgeBatches(revs, 100, 2)
+	if len(batches) != 3 {
+		t.Fatalf("unexpected number of batches %d: %v", len(batches), batches)
+	}
+
+	purged := map[string][]string{}
+	for _, batch := range batches {
+		count := 0
+		for id, batchRevs := range batch {
+			count += len(batchRevs)
+			purged[id] = append(purged[id], batchRevs...)
+		}
+		if count > 2 {
+			t.Errorf("batch over the revision limit %v", batch)
+		}
+	}
+	if len(purged["doc-a"]) != 5 || purged["doc-a"][4] != "5-a" || len(purged["doc-b"]) != 1 {
+		t.Errorf("unexpected revisions across batches %v", purged)
+	}
+}
+
+func TestDatabase_PurgeAll(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	meta, err := database.Put("doc-1", map[string]interface{}{"email": "someone@example.com"}, nil)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	purged, err := database.PurgeAll("doc-1")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(purged) != 1 || purged[0] != meta.Rev {
+		t.Errorf("unexpected purged revisions %v", purged)
+	}
+
+	doc := map[string]interface{}{}
+	err = database.Get("doc-1", &getQuery{}, &doc)
+	if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 {
+		t.Errorf("expected a 404 for a purged document, got %v", err)
+	}
+}
+
+func TestDatabase_Limits(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	if err = database.SetRevsLimit(500); err != nil {
+		t.Fatalf("%s", err)
+	}
+	limit, err := database.RevsLimit()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if limit != 500 {
+		t.Errorf("unexpected revs limit %d", limit)
+	}
+}
@@ -0,0 +1,100 @@
+package cloudant
+
+// QueryBuilder implementation for the Put() API call.
+//
+// Example:
+// 	query := NewPutQuery().
+//     Rev("1-967a00dff5e02add41819138abb3284d").
+//     W(2).
+//     Build()
+//
+//	meta, err := db.Put(docID, doc, query)
+
+import (
+	"net/url"
+	"strconv"
+)
+
+// PutQueryBuilder defines the available parameter-setting functions.
+type PutQueryBuilder interface {
+	Batch() PutQueryBuilder
+	NewEdits(bool) PutQueryBuilder
+	Rev(string) PutQueryBuilder
+	W(int) PutQueryBuilder
+	Build() *putQuery
+}
+
+type putQueryBuilder struct {
+	batch    bool
+	newEdits *bool
+	rev      string
+	w        int
+}
+
+// putQuery holds the implemented API call parameters.
+type putQuery struct {
+	Batch    bool
+	NewEdits *bool
+	Rev      string
+	W        int
+}
+
+// NewPutQuery is the entry point.
+func NewPutQuery() PutQueryBuilder {
+	return &putQueryBuilder{}
+}
+
+// Batch has the server acknowledge the write before committing it to disk,
+// in which case the response holds no revision.
+func (p *putQueryBuilder) Batch() PutQueryBuilder {
+	p.batch = true
+	return p
+}
+
+// NewEdits set to false stores the document with the revision given in its
+// _rev attribute as is, as replication does.
+func (p *putQueryBuilder) NewEdits(newEdits bool) PutQueryBuilder {
+	p.newEdits = &newEdits
+	return p
+}
+
+func (p *putQueryBuilder) Rev(rev string) PutQueryBuilder {
+	p.rev = rev
+	return p
+}
+
+// W is the number of replicas that must acknowledge the write.
+func (p *putQueryBuilder) W(w int) PutQueryBuilder {
+	p.w = w
+	return p
+}
+
+// GetQuery implements the QueryBuilder interface. It returns an
+// url.Values map with the non-default values set.
+func (pq *putQuery) GetQuery() (url.Values, error) {
+	vals := url.Values{}
+
+	if pq.Batch {
+		vals.Set("batch", "ok")
+	}
+	if pq.NewEdits != nil {
+		vals.Set("new_edits", strconv.FormatBool(*pq.NewEdits))
+	}
+	if pq.Rev != "" {
+		vals.Set("rev", pq.Rev)
+	}
+	if pq.W > 0 {
+		vals.Set("w", strconv.Itoa(pq.W))
+	}
+
+	return vals, nil
+}
+
+func (p *putQueryBuilder) Build() *putQuery {
+	return &putQuery{
+		Batch:    p.batch,
+		NewEdits: p.newEdits,
+		Rev:      p.rev,
+		W:        p.w,
+	}
+}
@@ -0,0 +1,41 @@
+package cloudant
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPutQuery_Args(t *testing.T) {
+	// Batch            bool
+	// NewEdits         *bool
+	// Rev              string
+	// W                int
+
+	expectedQueryStrings := []string{
+		"batch=ok",
+		"new_edits=false",
+		"rev=1-abc",
+		"w=2",
+	}
+
+	query := NewPutQuery().
+		Batch().
+		NewEdits(false).
+		Rev("1-abc").
+		W(2).
+		Build()
+
+	values, _ := query.GetQuery()
+	queryString := values.Encode()
+
+	for _, str := range expectedQueryStrings {
+		if !strings.Contains(queryString, str) {
+			t.Errorf("parameter encoding not found '%s'", str)
+		}
+	}
+
+	values, _ = NewPutQuery().Build().GetQuery()
+	if len(values) != 0 {
+		t.Errorf("unexpected default parameters '%s'", values.Encode())
+	}
+}
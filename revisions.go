@@ -0,0 +1,269 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// RevsDiffResult is the entry of a document in the response to a _revs_diff
+// request
+type RevsDiffResult struct {
+	Missing           []string `json:"missing"`
+	PossibleAncestors []string `json:"possible_ancestors,omitempty"`
+}
+
+// RevsDiff takes a map of document IDs to revisions, and reports which of
+// them are missing from the database, along with revisions the database
+// holds that may be their ancestors. Documents with no missing revisions are
+// left out of the result.
+// See: http://docs.couchdb.org/en/2.1.1/api/database/misc.html#db-revs-diff
+func (d *Database) RevsDiff(revs map[string][]string) (map[string]*RevsDiffResult, error) {
+	urlStr, err := Endpoint(*d.URL, "/_revs_diff", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(revs)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("POST", urlStr, bytes.NewReader(body))
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	diff := map[string]*RevsDiffResult{}
+	err = json.NewDecoder(job.response.Body).Decode(&diff)
+
+	return diff, err
+}
+
+// Revision is a node of a RevisionTree
+type Revision struct {
+	Rev      string
+	Status   string    // "available", "deleted" or "missing", as reported by revs_info
+	Parent   *Revision // nil for the oldest revision known
+	Children []*Revision
+}
+
+// Generation returns the generation number of the revision, the N of "N-hash".
+func (r *Revision) Generation() int {
+	return revGeneration(r.Rev)
+}
+
+// IsLeaf reports whether the revision ends a branch of the tree.
+func (r *Revision) IsLeaf() bool {
+	return len(r.Children) == 0
+}
+
+// Deleted reports whether the revision is a deletion tombstone.
+func (r *Revision) Deleted() bool {
+	return r.Status == "deleted"
+}
+
+// Available reports whether the body of the revision can still be fetched,
+// i.e. it hasn't been removed by compaction.
+func (r *Revision) Available() bool {
+	return r.Status == "available" || r.Status == "deleted"
+}
+
+// RevisionTree is the revision history of a document, with a branch for
+// each of its leaf revisions. Revisions pruned by the database's revs limit
+// are not included.
+type RevisionTree struct {
+	ID        string
+	database  *Database
+	revisions map[string]*Revision
+}
+
+// revsInfoDoc holds the revision history returned with a document
+type revsInfoDoc struct {
+	Rev       string `json:"_rev"`
+	Deleted   bool   `json:"_deleted"`
+	Revisions *struct {
+		Start int      `json:"start"`
+		IDs   []string `json:"ids"`
+	} `json:"_revisions"`
+	RevsInfo []struct {
+		Rev    string `json:"rev"`
+		Status string `json:"status"`
+	} `json:"_revs_info"`
+}
+
+// RevisionTree fetches the revision tree of a document. All of its leaf
+// revisions are found with open_revs=all, and the history of each is read
+// with revs_info.
+func (d *Database) RevisionTree(documentID string) (*RevisionTree, error) {
+	leaves, err := d.openRevs(documentID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	docs := make([]*revsInfoDoc, 0, len(leaves))
+	for _, leaf := range leaves {
+		rev, _ := leaf["_rev"].(string)
+
+		doc := &revsInfoDoc{}
+		err = d.Get(documentID, NewGetQuery().Rev(rev).RevsInfo().Build(), doc)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+
+	tree := newRevisionTree(documentID, docs)
+	tree.database = d
+
+	return tree, nil
+}
+
+// newRevisionTree builds a revision tree from leaf revisions fetched with
+// either revs or revs_info.
+func newRevisionTree(documentID string, docs []*revsInfoDoc) *RevisionTree {
+	tree := &RevisionTree{
+		ID:        documentID,
+		revisions: map[string]*Revision{},
+	}
+
+	for _, doc := range docs {
+		// history of the branch, newest revision first
+		history := []*Revision{}
+		switch {
+		case len(doc.RevsInfo) > 0:
+			for _, info := range doc.RevsInfo {
+				history = append(history, &Revision{Rev: info.Rev, Status: info.Status})
+			}
+		case doc.Revisions != nil:
+			for i, id := range doc.Revisions.IDs {
+				rev := strconv.Itoa(doc.Revisions.Start-i) + "-" + id
+				history = append(history, &Revision{Rev: rev})
+			}
+		default:
+			history = append(history, &Revision{Rev: doc.Rev})
+		}
+
+		if len(history) > 0 && history[0].Status == "" {
+			history[0].Status = "available"
+			if doc.Deleted {
+				history[0].Status = "deleted"
+			}
+		}
+
+		var child *Revision
+		for _, revision := range history {
+			existing, ok := tree.revisions[revision.Rev]
+			if ok {
+				if existing.Status == "" || existing.Status == "missing" {
+					existing.Status = revision.Status
+				}
+				revision = existing
+			} else {
+				tree.revisions[revision.Rev] = revision
+			}
+
+			if child != nil && child.Parent == nil {
+				child.Parent = revision
+				revision.Children = append(revision.Children, child)
+			}
+			if ok {
+				break // the rest of the branch is already known
+			}
+			child = revision
+		}
+	}
+
+	return tree
+}
+
+// Revision returns a revision of the tree, or nil if it isn't known.
+func (t *RevisionTree) Revision(rev string) *Revision {
+	return t.revisions[rev]
+}
+
+// Leaves returns the leaf revisions of the tree, the winning revision first,
+// followed by the others in the order the database ranks them.
+func (t *RevisionTree) Leaves() []*Revision {
+	leaves := []*Revision{}
+	for _, revision := range t.revisions {
+		if revision.IsLeaf() {
+			leaves = append(leaves, revision)
+		}
+	}
+
+	sort.Sort(leafRevisions(leaves))
+
+	return leaves
+}
+
+// leafRevisions sorts leaf revisions by rank, the winner first
+type leafRevisions []*Revision
+
+func (l leafRevisions) Len() int           { return len(l) }
+func (l leafRevisions) Swap(i, j int)      { l[i], l[j] = l[j], l[i] }
+func (l leafRevisions) Less(i, j int) bool { return winsOver(l[i], l[j]) }
+
+// Winner returns the winning revision, as chosen by the database: the leaf
+// that isn't deleted with the highest generation, ties broken by the highest
+// revision hash. Deleted leaves only win if every leaf is deleted.
+func (t *RevisionTree) Winner() *Revision {
+	leaves := t.Leaves()
+	if len(leaves) == 0 {
+		return nil
+	}
+	return leaves[0]
+}
+
+// Ancestry returns a revision followed by its ancestors, newest first, back
+// to the oldest revision known.
+func (t *RevisionTree) Ancestry(rev string) []*Revision {
+	ancestry := []*Revision{}
+	for revision := t.revisions[rev]; revision != nil; revision = revision.Parent {
+		ancestry = append(ancestry, revision)
+	}
+
+	return ancestry
+}
+
+// GetRevision fetches the body of a revision of the document into target.
+// Only revisions that are Available() can be fetched; the bodies of others
+// have been removed by compaction.
+func (t *RevisionTree) GetRevision(rev string, target interface{}) error {
+	return t.database.Get(t.ID, NewGetQuery().Rev(rev).Build(), target)
+}
+
+// winsOver reports whether leaf revision a ranks above b.
+func winsOver(a, b *Revision) bool {
+	if a.Deleted() != b.Deleted() {
+		return !a.Deleted()
+	}
+	if a.Generation() != b.Generation() {
+		return a.Generation() > b.Generation()
+	}
+	return revHash(a.Rev) > revHash(b.Rev)
+}
+
+// revGeneration returns the generation number of a revision, or 0 if it
+// can't be parsed.
+func revGeneration(rev string) int {
+	generation, _ := strconv.Atoi(strings.SplitN(rev, "-", 2)[0])
+	return generation
+}
+
+// revHash returns the hash part of a revision.
+func revHash(rev string) string {
+	parts := strings.SplitN(rev, "-", 2)
+	if len(parts) < 2 {
+		return ""
+	}
+	return parts[1]
+}
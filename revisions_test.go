@@ -0,0 +1,124 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestNewRevisionTree(t *testing.T) {
+	// Two branches off 2-bbb, one of them deleted, read with revs_info and
+	// revs respectively.
+	responses := []string{
+		`{"_id": "doc-1", "_rev": "3-aaa", "_revs_info": [
+			{"rev": "3-aaa", "status": "available"},
+			{"rev": "2-bbb", "status": "missing"},
+			{"rev": "1-ccc", "status": "missing"}]}`,
+		`{"_id": "doc-1", "_rev": "4-ddd", "_deleted": true, "_revisions": {"start": 4, "ids": ["ddd", "eee", "bbb"]}}`,
+	}
+
+	docs := []*revsInfoDoc{}
+	for _, response := range responses {
+		doc := &revsInfoDoc{}
+		if err := json.Unmarshal([]byte(response), doc); err != nil {
+			t.Fatalf("%s", err)
+		}
+		docs = append(docs, doc)
+	}
+
+	tree := newRevisionTree("doc-1", docs)
+
+	leaves := tree.Leaves()
+	if len(leaves) != 2 || leaves[0].Rev != "3-aaa" || leaves[1].Rev != "4-ddd" {
+		t.Fatalf("unexpected leaves %v", leaves)
+	}
+	if winner := tree.Winner(); winner.Rev != "3-aaa" {
+		t.Errorf("expected the deleted leaf to lose, got %s", winner.Rev)
+	}
+	if !leaves[1].Deleted() || !leaves[1].Available() {
+		t.Errorf("unexpected status of the deleted leaf %s", leaves[1].Status)
+	}
+
+	ancestry := tree.Ancestry("4-ddd")
+	expected := []string{"4-ddd", "3-eee", "2-bbb", "1-ccc"}
+	if len(ancestry) != len(expected) {
+		t.Fatalf("unexpected ancestry %v", ancestry)
+	}
+	for i, revision := range ancestry {
+		if revision.Rev != expected[i] {
+			t.Errorf("unexpected ancestor %s, expected %s", revision.Rev, expected[i])
+		}
+	}
+
+	shared := tree.Revision("2-bbb")
+	if shared == nil || len(shared.Children) != 2 || shared.Available() {
+		t.Errorf("unexpected shared ancestor %+v", shared)
+	}
+	if tree.Revision("3-eee").Status != "" {
+		t.Errorf("expected an unknown status for a revision read with revs")
+	}
+}
+
+func TestRevisionTree_Winner(t *testing.T) {
+	tree := newRevisionTree("doc-1", []*revsInfoDoc{
+		{Rev: "2-aaa"},
+		{Rev: "2-bbb"},
+		{Rev: "1-ccc"},
+	})
+	if winner := tree.Winner(); winner.Rev != "2-bbb" {
+		t.Errorf("expected the highest revision hash to win, got %s", winner.Rev)
+	}
+
+	tree = newRevisionTree("doc-1", []*revsInfoDoc{
+		{Rev: "3-aaa", Deleted: true},
+		{Rev: "2-bbb", Deleted: true},
+	})
+	if winner := tree.Winner(); winner.Rev != "3-aaa" {
+		t.Errorf("expected the longest deleted branch to win, got %s", winner.Rev)
+	}
+}
+
+func TestDatabase_RevisionTree(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	docs := []interface{}{
+		map[string]interface{}{"_id": "doc-1", "_rev": "1-aaa", "v": "a"},
+		map[string]interface{}{"_id": "doc-1", "_rev": "1-bbb", "v": "b"},
+	}
+	result, err := UploadBulkDocs(&BulkDocsRequest{Docs: docs, NewEdits: false}, database)
+	result.Close()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	diff, err := database.RevsDiff(map[string][]string{"doc-1": {"1-aaa", "2-ccc"}})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if result, ok := diff["doc-1"]; !ok || len(result.Missing) != 1 || result.Missing[0] != "2-ccc" {
+		t.Errorf("unexpected revs diff %v", diff)
+	}
+
+	tree, err := database.RevisionTree("doc-1")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(tree.Leaves()) != 2 || tree.Winner().Rev != "1-bbb" {
+		t.Errorf("unexpected leaves %v", tree.Leaves())
+	}
+
+	doc := map[string]interface{}{}
+	if err = tree.GetRevision("1-aaa", &doc); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if doc["v"] != "a" {
+		t.Errorf("unexpected revision body %v", doc)
+	}
+}
@@ -0,0 +1,137 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// SearchResponse is the JSON body of the response from a search index
+type SearchResponse struct {
+	TotalRows int                       `json:"total_rows"`
+	Bookmark  string                    `json:"bookmark"`
+	Rows      []SearchRow               `json:"rows"`
+	Counts    map[string]map[string]int `json:"counts"` // Only present if Counts() was set
+	Ranges    map[string]map[string]int `json:"ranges"` // Only present if Ranges() was set
+	Groups    []SearchGroup             `json:"groups"` // Only present if GroupField() was set
+}
+
+// SearchRow represents a single search result. Order holds the values that
+// the row was sorted by.
+type SearchRow struct {
+	ID         string                 `json:"id"`
+	Order      []interface{}          `json:"order"`
+	Fields     map[string]interface{} `json:"fields"`
+	Doc        json.RawMessage        `json:"doc"`        // Only present if IncludeDocs() was set
+	Highlights map[string][]string    `json:"highlights"` // Only present if HighlightFields() was set
+}
+
+// SearchGroup is a set of results sharing the same value of the group field
+type SearchGroup struct {
+	By        string      `json:"by"`
+	TotalRows int         `json:"total_rows"`
+	Rows      []SearchRow `json:"rows"`
+}
+
+// SearchInfo represents the meta-data of a search index
+type SearchInfo struct {
+	Name        string `json:"name"`
+	SearchIndex struct {
+		PendingSeq   int `json:"pending_seq"`
+		DocDelCount  int `json:"doc_del_count"`
+		DocCount     int `json:"doc_count"`
+		DiskSize     int `json:"disk_size"`
+		CommittedSeq int `json:"committed_seq"`
+	} `json:"search_index"`
+}
+
+// Search queries a Cloudant Search index.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/search.html#queries
+func (d *Database) Search(ddoc, index string, args *searchQuery) (*SearchResponse, error) {
+	params, err := args.GetQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*d.URL, designDocID(ddoc)+"/_search/"+index, params)
+	if err != nil {
+		return nil, err
+	}
+
+	return d.search(urlStr)
+}
+
+func (d *Database) search(urlStr string) (*SearchResponse, error) {
+	job, err := d.client.request("GET", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &SearchResponse{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+
+	return resp, err
+}
+
+// SearchInfo returns information about a search index.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/search.html#search-index-metadata
+func (d *Database) SearchInfo(ddoc, index string) (*SearchInfo, error) {
+	urlStr, err := Endpoint(*d.URL, designDocID(ddoc)+"/_search_info/"+index, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("GET", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	info := &SearchInfo{}
+	err = json.NewDecoder(job.response.Body).Decode(info)
+
+	return info, err
+}
+
+// SearchAnalyze returns the tokens that analyzer produces for text. It is
+// useful for checking how a search index will treat a field.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/search.html#testing-analyzer-tokenization
+func (c *CouchClient) SearchAnalyze(analyzer, text string) ([]string, error) {
+	body, err := json.Marshal(map[string]string{"analyzer": analyzer, "text": text})
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*c.rootURL, "/_search_analyze", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := c.request("POST", urlStr, bytes.NewReader(body))
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &struct {
+		Tokens []string `json:"tokens"`
+	}{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+
+	return resp.Tokens, err
+}
@@ -0,0 +1,300 @@
+package cloudant
+
+// QueryBuilder implementation for the Search() API call.
+//
+// Example:
+// 	query := NewSearchQuery().
+//     Query("name:jo* AND age:[20 TO 30]").
+//     Sort([]string{"-age<number>"}).
+//     Counts([]string{"city"}).
+//     IncludeDocs().
+//     Build()
+//
+//	result, err := db.Search("mydesigndoc", "myindex", query)
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// SearchQueryBuilder defines the available parameter-setting functions.
+type SearchQueryBuilder interface {
+	Bookmark(string) SearchQueryBuilder
+	Counts([]string) SearchQueryBuilder
+	Drilldown(string, string) SearchQueryBuilder
+	GroupField(string) SearchQueryBuilder
+	GroupLimit(int) SearchQueryBuilder
+	GroupSort([]string) SearchQueryBuilder
+	HighlightFields([]string) SearchQueryBuilder
+	HighlightNumber(int) SearchQueryBuilder
+	HighlightPostTag(string) SearchQueryBuilder
+	HighlightPreTag(string) SearchQueryBuilder
+	HighlightSize(int) SearchQueryBuilder
+	IncludeDocs() SearchQueryBuilder
+	IncludeFields([]string) SearchQueryBuilder
+	Limit(int) SearchQueryBuilder
+	Query(string) SearchQueryBuilder
+	Ranges(map[string]map[string]string) SearchQueryBuilder
+	Sort([]string) SearchQueryBuilder
+	Stale() SearchQueryBuilder
+	Build() *searchQuery
+}
+
+type searchQueryBuilder struct {
+	bookmark         string
+	counts           []string
+	drilldown        [][]string
+	groupField       string
+	groupLimit       int
+	groupSort        []string
+	highlightFields  []string
+	highlightNumber  int
+	highlightPostTag string
+	highlightPreTag  string
+	highlightSize    int
+	includeDocs      bool
+	includeFields    []string
+	limit            int
+	query            string
+	ranges           map[string]map[string]string
+	sort             []string
+	stale            bool
+}
+
+// searchQuery holds the implemented API call parameters.
+type searchQuery struct {
+	Bookmark         string
+	Counts           []string
+	Drilldown        [][]string
+	GroupField       string
+	GroupLimit       int
+	GroupSort        []string
+	HighlightFields  []string
+	HighlightNumber  int
+	HighlightPostTag string
+	HighlightPreTag  string
+	HighlightSize    int
+	IncludeDocs      bool
+	IncludeFields    []string
+	Limit            int
+	Query            string
+	Ranges           map[string]map[string]string
+	Sort             []string
+	Stale            bool
+}
+
+// NewSearchQuery is the entry point.
+func NewSearchQuery() SearchQueryBuilder {
+	return &searchQueryBuilder{}
+}
+
+func (s *searchQueryBuilder) Bookmark(bookmark string) SearchQueryBuilder {
+	s.bookmark = bookmark
+	return s
+}
+
+func (s *searchQueryBuilder) Counts(fields []string) SearchQueryBuilder {
+	s.counts = fields
+	return s
+}
+
+// Drilldown restricts results to documents where the faceted field has the
+// given value. It can be called multiple times to drill down on more than
+// one field.
+func (s *searchQueryBuilder) Drilldown(field, value string) SearchQueryBuilder {
+	s.drilldown = append(s.drilldown, []string{field, value})
+	return s
+}
+
+func (s *searchQueryBuilder) GroupField(field string) SearchQueryBuilder {
+	s.groupField = field
+	return s
+}
+
+func (s *searchQueryBuilder) GroupLimit(lim int) SearchQueryBuilder {
+	s.groupLimit = lim
+	return s
+}
+
+func (s *searchQueryBuilder) GroupSort(sort []string) SearchQueryBuilder {
+	s.groupSort = sort
+	return s
+}
+
+func (s *searchQueryBuilder) HighlightFields(fields []string) SearchQueryBuilder {
+	s.highlightFields = fields
+	return s
+}
+
+func (s *searchQueryBuilder) HighlightNumber(number int) SearchQueryBuilder {
+	s.highlightNumber = number
+	return s
+}
+
+func (s *searchQueryBuilder) HighlightPostTag(tag string) SearchQueryBuilder {
+	s.highlightPostTag = tag
+	return s
+}
+
+func (s *searchQueryBuilder) HighlightPreTag(tag string) SearchQueryBuilder {
+	s.highlightPreTag = tag
+	return s
+}
+
+func (s *searchQueryBuilder) HighlightSize(size int) SearchQueryBuilder {
+	s.highlightSize = size
+	return s
+}
+
+func (s *searchQueryBuilder) IncludeDocs() SearchQueryBuilder {
+	s.includeDocs = true
+	return s
+}
+
+func (s *searchQueryBuilder) IncludeFields(fields []string) SearchQueryBuilder {
+	s.includeFields = fields
+	return s
+}
+
+func (s *searchQueryBuilder) Limit(lim int) SearchQueryBuilder {
+	s.limit = lim
+	return s
+}
+
+// Query sets the Lucene query string.
+func (s *searchQueryBuilder) Query(q string) SearchQueryBuilder {
+	s.query = q
+	return s
+}
+
+// Ranges defines the named ranges that results are counted against, e.g.
+// {"price": {"cheap": "[0 TO 100]", "expensive": "{100 TO Infinity}"}}
+func (s *searchQueryBuilder) Ranges(ranges map[string]map[string]string) SearchQueryBuilder {
+	s.ranges = ranges
+	return s
+}
+
+func (s *searchQueryBuilder) Sort(sort []string) SearchQueryBuilder {
+	s.sort = sort
+	return s
+}
+
+func (s *searchQueryBuilder) Stale() SearchQueryBuilder {
+	s.stale = true
+	return s
+}
+
+// GetQuery implements the QueryBuilder interface. It returns an
+// url.Values map with the non-default values set.
+func (sq *searchQuery) GetQuery() (url.Values, error) {
+	vals := url.Values{}
+
+	if sq.Query != "" {
+		vals.Set("q", sq.Query)
+	}
+	if sq.Bookmark != "" {
+		vals.Set("bookmark", sq.Bookmark)
+	}
+	if len(sq.Counts) > 0 {
+		data, err := json.Marshal(sq.Counts)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("counts", string(data[:]))
+	}
+	for _, drilldown := range sq.Drilldown {
+		data, err := json.Marshal(drilldown)
+		if err != nil {
+			return nil, err
+		}
+		vals.Add("drilldown", string(data[:]))
+	}
+	if sq.GroupField != "" {
+		vals.Set("group_field", sq.GroupField)
+	}
+	if sq.GroupLimit > 0 {
+		vals.Set("group_limit", strconv.Itoa(sq.GroupLimit))
+	}
+	if len(sq.GroupSort) > 0 {
+		data, err := json.Marshal(sq.GroupSort)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("group_sort", string(data[:]))
+	}
+	if len(sq.HighlightFields) > 0 {
+		data, err := json.Marshal(sq.HighlightFields)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("highlight_fields", string(data[:]))
+	}
+	if sq.HighlightNumber > 0 {
+		vals.Set("highlight_number", strconv.Itoa(sq.HighlightNumber))
+	}
+	if sq.HighlightPostTag != "" {
+		vals.Set("highlight_post_tag", sq.HighlightPostTag)
+	}
+	if sq.HighlightPreTag != "" {
+		vals.Set("highlight_pre_tag", sq.HighlightPreTag)
+	}
+	if sq.HighlightSize > 0 {
+		vals.Set("highlight_size", strconv.Itoa(sq.HighlightSize))
+	}
+	if sq.IncludeDocs {
+		vals.Set("include_docs", "true")
+	}
+	if len(sq.IncludeFields) > 0 {
+		data, err := json.Marshal(sq.IncludeFields)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("include_fields", string(data[:]))
+	}
+	if sq.Limit > 0 {
+		vals.Set("limit", strconv.Itoa(sq.Limit))
+	}
+	if len(sq.Ranges) > 0 {
+		data, err := json.Marshal(sq.Ranges)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("ranges", string(data[:]))
+	}
+	if len(sq.Sort) > 0 {
+		data, err := json.Marshal(sq.Sort)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("sort", string(data[:]))
+	}
+	if sq.Stale {
+		vals.Set("stale", "ok")
+	}
+
+	return vals, nil
+}
+
+func (s *searchQueryBuilder) Build() *searchQuery {
+	return &searchQuery{
+		Bookmark:         s.bookmark,
+		Counts:           s.counts,
+		Drilldown:        s.drilldown,
+		GroupField:       s.groupField,
+		GroupLimit:       s.groupLimit,
+		GroupSort:        s.groupSort,
+		HighlightFields:  s.highlightFields,
+		HighlightNumber:  s.highlightNumber,
+		HighlightPostTag: s.highlightPostTag,
+		HighlightPreTag:  s.highlightPreTag,
+		HighlightSize:    s.highlightSize,
+		IncludeDocs:      s.includeDocs,
+		IncludeFields:    s.includeFields,
+		Limit:            s.limit,
+		Query:            s.query,
+		Ranges:           s.ranges,
+		Sort:             s.sort,
+		Stale:            s.stale,
+	}
+}
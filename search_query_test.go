@@ -0,0 +1,74 @@
+package cloudant
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSearchQuery_Args(t *testing.T) {
+	// Bookmark         string
+	// Counts           []string
+	// Drilldown        [][]string
+	// GroupField       string
+	// GroupLimit       int
+	// HighlightFields  []string
+	// HighlightNumber  int
+	// HighlightPostTag string
+	// HighlightPreTag  string
+	// HighlightSize    int
+	// IncludeDocs      bool
+	// Limit            int
+	// Query            string
+	// Ranges           map[string]map[string]string
+	// Sort             []string
+	// Stale            bool
+
+	expectedQueryStrings := []string{
+		"bookmark=g1AAAA",
+		"counts=%5B%22city%22%5D",
+		"drilldown=%5B%22city%22%2C%22Bristol%22%5D&drilldown=%5B%22type%22%2C%22flat%22%5D",
+		"group_field=city",
+		"group_limit=3",
+		"highlight_fields=%5B%22description%22%5D",
+		"highlight_number=2",
+		"highlight_post_tag=%3C%2Fb%3E",
+		"highlight_pre_tag=%3Cb%3E",
+		"highlight_size=50",
+		"include_docs=true",
+		"limit=5",
+		"q=name%3Ajo%2A",
+		"ranges=%7B%22price%22%3A%7B%22cheap%22%3A%22%5B0+TO+100%5D%22%7D%7D",
+		"sort=%5B%22-price%22%5D",
+		"stale=ok",
+	}
+
+	query := NewSearchQuery().
+		Bookmark("g1AAAA").
+		Counts([]string{"city"}).
+		Drilldown("city", "Bristol").
+		Drilldown("type", "flat").
+		GroupField("city").
+		GroupLimit(3).
+		HighlightFields([]string{"description"}).
+		HighlightNumber(2).
+		HighlightPostTag("</b>").
+		HighlightPreTag("<b>").
+		HighlightSize(50).
+		IncludeDocs().
+		Limit(5).
+		Query("name:jo*").
+		Ranges(map[string]map[string]string{"price": {"cheap": "[0 TO 100]"}}).
+		Sort([]string{"-price"}).
+		Stale().
+		Build()
+
+	values, _ := query.GetQuery()
+	queryString := values.Encode()
+
+	for _, str := range expectedQueryStrings {
+		if !strings.Contains(queryString, str) {
+			t.Errorf("parameter encoding not found '%s' in '%s'", str, queryString)
+			return
+		}
+	}
+}
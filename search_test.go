@@ -0,0 +1,107 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestSearchResponse_Decode(t *testing.T) {
+	data := []byte(`{
+		"total_rows": 2,
+		"bookmark": "g1AAAAB",
+		"rows": [
+			{"id": "doc-001", "order": [1.5, 0], "fields": {"foo": "bar"}, "highlights": {"foo": ["<em>bar</em>"]}},
+			{"id": "doc-002", "order": [1.0, 1], "fields": {"foo": "baz"}}
+		],
+		"counts": {"foo": {"bar": 1, "baz": 1}},
+		"ranges": {"bar": {"low": 2, "high": 0}}
+	}`)
+
+	resp := &SearchResponse{}
+	if err := json.Unmarshal(data, resp); err != nil {
+		t.Fatal(err)
+	}
+
+	if resp.TotalRows != 2 || len(resp.Rows) != 2 || resp.Bookmark != "g1AAAAB" {
+		t.Errorf("unexpected response %+v", resp)
+	}
+	if resp.Rows[0].Highlights["foo"][0] != "<em>bar</em>" {
+		t.Errorf("unexpected highlights %+v", resp.Rows[0].Highlights)
+	}
+	if resp.Counts["foo"]["baz"] != 1 || resp.Ranges["bar"]["low"] != 2 {
+		t.Errorf("unexpected facets %+v %+v", resp.Counts, resp.Ranges)
+	}
+
+	grouped := []byte(`{"total_rows": 1, "groups": [{"by": "bar", "total_rows": 1, "rows": [{"id": "doc-001"}]}]}`)
+	resp = &SearchResponse{}
+	if err := json.Unmarshal(grouped, resp); err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Groups) != 1 || resp.Groups[0].By != "bar" || resp.Groups[0].Rows[0].ID != "doc-001" {
+		t.Errorf("unexpected groups %+v", resp.Groups)
+	}
+}
+
+func TestDatabase_Search(t *testing.T) {
+	if travis() {
+		fmt.Printf("[SKIP] TestDatabase_Search requires Cloudant")
+		return
+	}
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	makeDocuments(database, 100)
+
+	ddoc := map[string]interface{}{
+		"_id": "_design/test",
+		"indexes": map[string]interface{}{
+			"bar": map[string]string{
+				"index": "function(doc) { index('bar', doc.bar, {facet: true}); }",
+			},
+		},
+	}
+	if _, err = database.Set(ddoc); err != nil {
+		t.Fatalf("failed to create design document: %s", err)
+	}
+
+	// allow the search index to build
+	time.Sleep(5 * time.Second)
+
+	query := NewSearchQuery().
+		Query("bar:123").
+		Counts([]string{"bar"}).
+		Limit(10).
+		Build()
+
+	result, err := database.Search("test", "bar", query)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if result.TotalRows != 100 || len(result.Rows) != 10 || result.Bookmark == "" {
+		t.Errorf("unexpected search result %d rows, %d total", len(result.Rows), result.TotalRows)
+	}
+
+	info, err := database.SearchInfo("test", "bar")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if info.SearchIndex.DocCount != 100 {
+		t.Errorf("unexpected search index doc count %d", info.SearchIndex.DocCount)
+	}
+
+	tokens, err := database.client.SearchAnalyze("english", "running dogs")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(tokens) != 2 || tokens[0] != "run" {
+		t.Errorf("unexpected tokens %v", tokens)
+	}
+}
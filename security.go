@@ -0,0 +1,203 @@
+package cloudant
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+)
+
+// Cloudant permissions, granted to users and API keys in the "cloudant"
+// section of a security object
+const (
+	PermissionReader     = "_reader"
+	PermissionWriter     = "_writer"
+	PermissionAdmin      = "_admin"
+	PermissionReplicator = "_replicator"
+	PermissionDesign     = "_design"
+)
+
+// SecurityGroup lists the users and roles of the admins or members of a
+// database
+type SecurityGroup struct {
+	Names []string `json:"names,omitempty"`
+	Roles []string `json:"roles,omitempty"`
+}
+
+// Security is the security object of a database. Admins and Members are
+// used by CouchDB, and Cloudant maps the names of users and API keys to
+// their permissions. Other members of the security object, such as
+// Cloudant's couchdb_auth_only, are kept as they were read.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/authorization.html
+type Security struct {
+	Admins   SecurityGroup       `json:"admins"`
+	Members  SecurityGroup       `json:"members"`
+	Cloudant map[string][]string `json:"cloudant,omitempty"` // Written if non-nil, even when empty
+
+	unknown map[string]json.RawMessage
+}
+
+// securityJSON has the fields of Security without its methods
+type securityJSON Security
+
+// MarshalJSON implements the json.Marshaler interface.
+func (s Security) MarshalJSON() ([]byte, error) {
+	unknown := s.unknown
+	if s.Cloudant != nil && len(s.Cloudant) == 0 {
+		// an emptied permission map must still be written to take effect
+		unknown = map[string]json.RawMessage{"cloudant": json.RawMessage("{}")}
+		for key, value := range s.unknown {
+			unknown[key] = value
+		}
+	}
+
+	return marshalWithUnknown(securityJSON(s), unknown)
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+func (s *Security) UnmarshalJSON(data []byte) error {
+	security := securityJSON{}
+	if err := json.Unmarshal(data, &security); err != nil {
+		return err
+	}
+
+	unknown, err := unknownFields(data, security)
+	if err != nil {
+		return err
+	}
+
+	*s = Security(security)
+	s.unknown = unknown
+
+	return nil
+}
+
+// Grant adds permissions of a user or API key to the Cloudant permission
+// map, keeping those it already has.
+func (s *Security) Grant(username string, permissions ...string) {
+	if s.Cloudant == nil {
+		s.Cloudant = map[string][]string{}
+	}
+
+	granted := s.Cloudant[username]
+	for _, permission := range permissions {
+		if !containsString(granted, permission) {
+			granted = append(granted, permission)
+		}
+	}
+	sort.Strings(granted)
+
+	s.Cloudant[username] = granted
+}
+
+// Revoke removes permissions of a user or API key from the Cloudant
+// permission map, or all of them if none are given. Users left without
+// permissions are removed from the map.
+func (s *Security) Revoke(username string, permissions ...string) {
+	remaining := []string{}
+	if len(permissions) > 0 {
+		for _, permission := range s.Cloudant[username] {
+			if !containsString(permissions, permission) {
+				remaining = append(remaining, permission)
+			}
+		}
+	}
+
+	if len(remaining) == 0 {
+		delete(s.Cloudant, username)
+		return
+	}
+	s.Cloudant[username] = remaining
+}
+
+// containsString reports whether values holds value.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// GetSecurity returns the security object of the database.
+func (d *Database) GetSecurity() (*Security, error) {
+	urlStr, err := Endpoint(*d.URL, "/_security", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("GET", urlStr, nil)
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	security := &Security{}
+	err = json.NewDecoder(job.response.Body).Decode(security)
+
+	return security, err
+}
+
+// SetSecurity replaces the security object of the database.
+func (d *Database) SetSecurity(security *Security) error {
+	urlStr, err := Endpoint(*d.URL, "/_security", nil)
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(security)
+	if err != nil {
+		return err
+	}
+
+	job, err := d.client.request("PUT", urlStr, bytes.NewReader(body))
+	defer job.Close()
+	if err != nil {
+		return err
+	}
+
+	return expectedReturnCodes(job, 200)
+}
+
+// UpdateSecurity reads the security object of the database, calls mutate
+// to modify it and writes it back. Security objects have no revisions, so
+// concurrent updates are not detected: the last write wins.
+func (d *Database) UpdateSecurity(mutate func(security *Security) error) error {
+	security, err := d.GetSecurity()
+	if err != nil {
+		return err
+	}
+
+	if err = mutate(security); err != nil {
+		return err
+	}
+
+	return d.SetSecurity(security)
+}
+
+// GrantPermissions gives a user or API key Cloudant permissions on the
+// database, keeping those it already has.
+//
+// Example:
+//
+//	err := db.GrantPermissions(apiKey, cloudant.PermissionReader)
+func (d *Database) GrantPermissions(username string, permissions ...string) error {
+	return d.UpdateSecurity(func(security *Security) error {
+		security.Grant(username, permissions...)
+		return nil
+	})
+}
+
+// RevokePermissions takes Cloudant permissions on the database away from a
+// user or API key, or all of them if none are given.
+func (d *Database) RevokePermissions(username string, permissions ...string) error {
+	return d.UpdateSecurity(func(security *Security) error {
+		security.Revoke(username, permissions...)
+		return nil
+	})
+}
@@ -0,0 +1,123 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestSecurity_GrantRevoke(t *testing.T) {
+	security := &Security{}
+
+	security.Grant("apikey", PermissionWriter, PermissionReader)
+	security.Grant("apikey", PermissionReader)
+	granted := security.Cloudant["apikey"]
+	if len(granted) != 2 || granted[0] != PermissionReader || granted[1] != PermissionWriter {
+		t.Errorf("unexpected permissions %v", granted)
+	}
+
+	security.Revoke("apikey", PermissionWriter)
+	granted = security.Cloudant["apikey"]
+	if len(granted) != 1 || granted[0] != PermissionReader {
+		t.Errorf("unexpected permissions %v", granted)
+	}
+
+	security.Grant("other", PermissionAdmin)
+	security.Revoke("other")
+	if _, ok := security.Cloudant["other"]; ok {
+		t.Errorf("expected all permissions to be revoked, got %v", security.Cloudant)
+	}
+
+	security.Revoke("apikey", PermissionReader)
+	if len(security.Cloudant) != 0 {
+		t.Errorf("expected an empty permission map, got %v", security.Cloudant)
+	}
+}
+
+func TestSecurity_JSON(t *testing.T) {
+	data := `{
+		"admins": {"names": ["admin"], "roles": []},
+		"members": {"roles": ["staff"]},
+		"cloudant": {"nobody": [], "apikey": ["_reader", "_writer"]}
+	}`
+
+	security := &Security{}
+	if err := json.Unmarshal([]byte(data), security); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if security.Admins.Names[0] != "admin" || security.Members.Roles[0] != "staff" {
+		t.Errorf("unexpected security object %+v", security)
+	}
+	if len(security.Cloudant["apikey"]) != 2 {
+		t.Errorf("unexpected permission map %v", security.Cloudant)
+	}
+
+	body, err := json.Marshal(&Security{})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if string(body) != `{"admins":{},"members":{}}` {
+		t.Errorf("unexpected empty security object %s", body)
+	}
+}
+
+func TestSecurity_RoundTrip(t *testing.T) {
+	data := `{"admins":{"names":["admin"]},"members":{},"cloudant":{"apikey":["_reader"]},"couchdb_auth_only":true}`
+
+	security := &Security{}
+	if err := json.Unmarshal([]byte(data), security); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	security.Revoke("apikey")
+
+	body, err := json.Marshal(security)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	written := map[string]interface{}{}
+	if err = json.Unmarshal(body, &written); err != nil {
+		t.Fatalf("%s", err)
+	}
+	if written["couchdb_auth_only"] != true {
+		t.Errorf("expected the unknown field to be kept, got %s", body)
+	}
+	if cloudant, ok := written["cloudant"].(map[string]interface{}); !ok || len(cloudant) != 0 {
+		t.Errorf("expected an empty permission map to be written, got %s", body)
+	}
+	if admins, ok := written["admins"].(map[string]interface{}); !ok || admins["names"] == nil {
+		t.Errorf("unexpected admins in %s", body)
+	}
+}
+
+func TestDatabase_Security(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	err = database.SetSecurity(&Security{Admins: SecurityGroup{Roles: []string{"_admin"}}})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if err = database.GrantPermissions("apikey", PermissionReader); err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	security, err := database.GetSecurity()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if len(security.Admins.Roles) != 1 || security.Admins.Roles[0] != "_admin" {
+		t.Errorf("expected the admins to be kept, got %+v", security.Admins)
+	}
+	if granted := security.Cloudant["apikey"]; len(granted) != 1 || granted[0] != PermissionReader {
+		t.Errorf("unexpected permissions %v", security.Cloudant)
+	}
+}
@@ -0,0 +1,263 @@
+//go:build go1.18
+// +build go1.18
+
+package cloudant
+
+import (
+	"encoding/json"
+)
+
+// TypedDatabase is a handle on a database holding documents of type T. Its
+// methods decode documents into T rather than into interface{} values. If T
+// is a struct, the fields tagged `json:"_id"` and `json:"_rev"` hold the
+// document's ID and revision, and are kept up to date as it is written.
+//
+// Example:
+//
+//	type Person struct {
+//		ID   string `json:"_id,omitempty"`
+//		Rev  string `json:"_rev,omitempty"`
+//		Name string `json:"name"`
+//	}
+//
+//	people := cloudant.Typed[Person](db)
+//	person := &Person{Name: "Alice"}
+//	_, err := people.Set(person) // person.ID and person.Rev are now set
+type TypedDatabase[T any] struct {
+	database *Database
+}
+
+// TypedAllRow is a row returned by TypedDatabase.All()
+type TypedAllRow[T any] struct {
+	ID  string
+	Rev string
+	Doc *T
+	Err error // Set if the document couldn't be decoded into T
+}
+
+// TypedChange is a change returned by TypedDatabase.Changes()
+type TypedChange[T any] struct {
+	ID      string
+	Rev     string
+	Seq     string
+	Deleted bool
+	Doc     *T
+	Err     error // Set if the document couldn't be decoded into T
+}
+
+// TypedChangeEvent is an event returned by TypedDatabase.Follow()
+type TypedChangeEvent[T any] struct {
+	EventType int
+	Meta      *DocumentMeta
+	Seq       string
+	Doc       *T
+	Err       error
+}
+
+// typedAllRowJSON is a row of _all_docs with its document decoded into T
+type typedAllRowJSON[T any] struct {
+	ID    string      `json:"id"`
+	Value AllRowValue `json:"value"`
+	Doc   *T          `json:"doc"`
+}
+
+// typedDocJSON is a row of a changes feed with its document decoded into T
+type typedDocJSON[T any] struct {
+	Doc *T `json:"doc"`
+}
+
+// Typed returns a handle on db for documents of type T.
+func Typed[T any](db *Database) *TypedDatabase[T] {
+	return &TypedDatabase[T]{database: db}
+}
+
+// Database returns the underlying untyped database handle.
+func (t *TypedDatabase[T]) Database() *Database {
+	return t.database
+}
+
+// Get fetches a document.
+func (t *TypedDatabase[T]) Get(documentID string, args *getQuery) (*T, error) {
+	doc := new(T)
+	err := t.database.Get(documentID, args, doc)
+	if err != nil {
+		return nil, err
+	}
+
+	return doc, nil
+}
+
+// Set writes a document, and sets its _id and _rev fields to those of the
+// new revision.
+func (t *TypedDatabase[T]) Set(doc *T) (*DocumentMeta, error) {
+	meta, err := t.database.Set(doc)
+	if err != nil {
+		return nil, err
+	}
+
+	setDocumentMeta(doc, meta)
+
+	return meta, nil
+}
+
+// Delete deletes a document, given with its _id and _rev fields set.
+func (t *TypedDatabase[T]) Delete(doc *T) error {
+	id, rev := documentMeta(doc)
+
+	return t.database.Delete(id, rev)
+}
+
+// Update runs a read-modify-write cycle on a document, as Database.Update()
+// does, and returns the saved document with its new _rev. If mutate returns
+// ErrDeleteDocument the document is deleted and nil is returned, along with
+// ErrNothingToDelete if it didn't exist.
+func (t *TypedDatabase[T]) Update(documentID string, mutate func(doc *T) error) (*T, error) {
+	return t.UpdateWithOptions(documentID, mutate, DefaultUpdateOptions)
+}
+
+// UpdateWithOptions is like Update, with the options of
+// Database.UpdateWithOptions().
+func (t *TypedDatabase[T]) UpdateWithOptions(documentID string, mutate func(doc *T) error,
+	options UpdateOptions) (*T, error) {
+
+	doc := new(T)
+	deleted := false
+
+	meta, err := t.database.UpdateWithOptions(documentID, doc, func() error {
+		err := mutate(doc)
+		deleted = err == ErrDeleteDocument
+		return err
+	}, options)
+	if err != nil || deleted {
+		return nil, err
+	}
+
+	setDocumentMeta(doc, meta)
+
+	return doc, nil
+}
+
+// All returns a channel in which the rows of _all_docs can be received,
+// with their documents decoded into T. Documents are always included.
+func (t *TypedDatabase[T]) All(args *allDocsQuery) (<-chan *TypedAllRow[T], error) {
+	query := *args
+	query.IncludeDocs = true
+
+	lines, err := t.database.allDocsLines("/_all_docs", &query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan *TypedAllRow[T], 1000)
+
+	go func() {
+		defer close(results)
+
+		for line := range lines {
+			row := &typedAllRowJSON[T]{}
+			err := json.Unmarshal(line, row)
+			if err != nil {
+				row.Doc = nil
+			}
+			results <- &TypedAllRow[T]{ID: row.ID, Rev: row.Value.Rev, Doc: row.Doc, Err: err}
+		}
+	}()
+
+	return results, nil
+}
+
+// Changes returns a channel in which changes can be received, with their
+// documents decoded into T. Documents are always included.
+func (t *TypedDatabase[T]) Changes(args *changesQuery) (<-chan *TypedChange[T], error) {
+	query := *args
+	query.IncludeDocs = true
+
+	lines, err := t.database.changesLines(&query)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make(chan *TypedChange[T], 1000)
+
+	go func() {
+		defer close(results)
+
+		for line := range lines {
+			change := new(ChangeRow)
+			if err := json.Unmarshal(line, change); err != nil || len(change.Changes) != 1 {
+				continue
+			}
+
+			doc, err := decodeTypedDoc[T](line)
+			results <- &TypedChange[T]{
+				ID:      change.ID,
+				Rev:     change.Changes[0].Rev,
+				Seq:     change.Seq,
+				Deleted: change.Deleted,
+				Doc:     doc,
+				Err:     err,
+			}
+		}
+	}()
+
+	return results, nil
+}
+
+// Follow starts follower listening to the changes feed, and returns a
+// channel in which its events can be received, with their documents decoded
+// into T. The follower must have been created on the same database, and is
+// stopped with its Close() method.
+func (t *TypedDatabase[T]) Follow(follower *Follower) (<-chan *TypedChangeEvent[T], error) {
+	results := make(chan *TypedChangeEvent[T], 1000)
+
+	err := follower.follow(func(event *ChangeEvent, line []byte) {
+		typed := &TypedChangeEvent[T]{
+			EventType: event.EventType,
+			Meta:      event.Meta,
+			Seq:       event.Seq,
+			Err:       event.Err,
+		}
+		if line != nil {
+			typed.Doc, typed.Err = decodeTypedDoc[T](line)
+		}
+		results <- typed
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// decodeTypedDoc decodes the document of a row of a changes feed straight
+// into T, returning nil if there is no document.
+func decodeTypedDoc[T any](line []byte) (*T, error) {
+	row := &typedDocJSON[T]{}
+	if err := json.Unmarshal(line, row); err != nil {
+		return nil, err
+	}
+
+	return row.Doc, nil
+}
+
+// documentMeta reads the _id and _rev fields of a document.
+func documentMeta(doc interface{}) (id, rev string) {
+	if fieldName, ok := fieldNameByJSONTag(doc, "_id"); ok {
+		id, _ = getByFieldName(doc, fieldName)
+	}
+	if fieldName, ok := fieldNameByJSONTag(doc, "_rev"); ok {
+		rev, _ = getByFieldName(doc, fieldName)
+	}
+
+	return id, rev
+}
+
+// setDocumentMeta sets the _id and _rev fields of a document.
+func setDocumentMeta(doc interface{}, meta *DocumentMeta) {
+	if fieldName, ok := fieldNameByJSONTag(doc, "_id"); ok && meta.ID != "" {
+		setByFieldName(doc, fieldName, meta.ID)
+	}
+	if fieldName, ok := fieldNameByJSONTag(doc, "_rev"); ok && meta.Rev != "" {
+		setByFieldName(doc, fieldName, meta.Rev)
+	}
+}
@@ -0,0 +1,169 @@
+//go:build go1.18
+// +build go1.18
+
+package cloudant
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type typedTestDoc struct {
+	ID    string `json:"_id,omitempty"`
+	Rev   string `json:"_rev,omitempty"`
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+	Big   int64  `json:"big,omitempty"`
+}
+
+func TestTyped_DocumentMeta(t *testing.T) {
+	doc := &typedTestDoc{ID: "doc-1", Rev: "1-abc"}
+
+	id, rev := documentMeta(doc)
+	if id != "doc-1" || rev != "1-abc" {
+		t.Errorf("unexpected meta-data %s %s", id, rev)
+	}
+
+	setDocumentMeta(doc, &DocumentMeta{ID: "doc-1", Rev: "2-def"})
+	if doc.ID != "doc-1" || doc.Rev != "2-def" {
+		t.Errorf("unexpected document %+v", doc)
+	}
+
+	// documents without _id and _rev fields are left alone
+	other := &struct{ Name string }{}
+	setDocumentMeta(other, &DocumentMeta{ID: "doc-1", Rev: "2-def"})
+	if id, rev = documentMeta(other); id != "" || rev != "" {
+		t.Errorf("unexpected meta-data %s %s", id, rev)
+	}
+}
+
+func TestTyped_DecodeTypedDoc(t *testing.T) {
+	line := []byte(`{"seq":"1-abc","id":"doc-1","changes":[{"rev":"1-abc"}],"doc":{"_id":"doc-1","name":"a","count":2,"big":9007199254740993}}`)
+	doc, err := decodeTypedDoc[typedTestDoc](line)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if doc.ID != "doc-1" || doc.Name != "a" || doc.Count != 2 || doc.Big != 9007199254740993 {
+		t.Errorf("unexpected document %+v", doc)
+	}
+
+	if doc, err = decodeTypedDoc[typedTestDoc]([]byte(`{"seq":"2-def","id":"doc-1","deleted":true}`)); doc != nil || err != nil {
+		t.Errorf("expected no document, got %+v %v", doc, err)
+	}
+
+	if _, err = decodeTypedDoc[typedTestDoc]([]byte(`{"doc":{"count":"two"}}`)); err == nil {
+		t.Errorf("expected a decoding error")
+	}
+}
+
+func TestTypedDatabase_AllArgs(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_session" {
+			return
+		}
+		if r.URL.Query().Get("include_docs") != "true" {
+			t.Errorf("expected documents to be included, got %s", r.URL.RawQuery)
+		}
+		fmt.Fprint(w, "{\"total_rows\":1,\"offset\":0,\"rows\":[\n"+
+			`{"id":"doc-1","key":"doc-1","value":{"rev":"1-abc"},"doc":{"_id":"doc-1","_rev":"1-abc","big":9007199254740993}}`+
+			"\n]}\n")
+	}))
+	defer server.Close()
+
+	client, err := CreateClientWithRetry("user", "pass", server.URL, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	database, err := client.Get("db")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	args := NewAllDocsQuery().Build()
+	rows, err := Typed[typedTestDoc](database).All(args)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if args.IncludeDocs {
+		t.Error("caller's query was modified")
+	}
+
+	row := <-rows
+	if row == nil || row.Err != nil || row.Rev != "1-abc" || row.Doc.Big != 9007199254740993 {
+		t.Errorf("unexpected row %+v", row)
+	}
+}
+
+func TestTypedDatabase(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	docs := Typed[typedTestDoc](database)
+
+	doc := &typedTestDoc{Name: "a"}
+	meta, err := docs.Set(doc)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if doc.ID != meta.ID || doc.Rev != meta.Rev {
+		t.Errorf("expected the meta-data to be written back, got %+v", doc)
+	}
+
+	updated, err := docs.Update(doc.ID, func(doc *typedTestDoc) error {
+		doc.Count++
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if updated.Count != 1 || updated.Rev == doc.Rev {
+		t.Errorf("unexpected updated document %+v", updated)
+	}
+
+	fetched, err := docs.Get(doc.ID, &getQuery{})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if *fetched != *updated {
+		t.Errorf("unexpected document %+v, expected %+v", fetched, updated)
+	}
+
+	rows, err := docs.All(NewAllDocsQuery().Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	count := 0
+	for row := range rows {
+		if row.Err != nil || row.Doc.Name != "a" {
+			t.Errorf("unexpected row %+v", row)
+		}
+		count++
+	}
+	if count != 1 {
+		t.Errorf("unexpected row count %d", count)
+	}
+
+	changes, err := docs.Changes(NewChangesQuery().Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	for change := range changes {
+		if change.Err != nil || change.Doc.Count != 1 {
+			t.Errorf("unexpected change %+v", change)
+		}
+	}
+
+	if err = docs.Delete(updated); err != nil {
+		t.Fatalf("%s", err)
+	}
+}
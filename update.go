@@ -0,0 +1,121 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"time"
+)
+
+// ErrDeleteDocument can be returned by the mutate function given to Update()
+// to have the document deleted rather than saved.
+var ErrDeleteDocument = errors.New("delete document")
+
+// ErrNothingToDelete is returned by Update() when mutate asks for a document
+// that doesn't exist to be deleted.
+var ErrNothingToDelete = errors.New("no document to delete")
+
+// UpdateOptions configures how Update() retries conflicting writes.
+type UpdateOptions struct {
+	MaxAttempts     int           // Attempts made before a conflict is returned to the caller
+	Backoff         time.Duration // Delay before the first retry, doubled for each further one
+	CreateIfMissing bool          // Run mutate on an empty target if the document doesn't exist
+}
+
+// DefaultUpdateOptions are the options used by Update().
+var DefaultUpdateOptions = UpdateOptions{
+	MaxAttempts: 5,
+	Backoff:     100 * time.Millisecond,
+}
+
+// Update runs a read-modify-write cycle on a document. The current revision
+// of the document is fetched into target, which must be a pointer, mutate is
+// called to modify target, and the result is saved. If the save fails with a
+// 409 conflict the cycle is repeated with a fresh copy of the document, so
+// mutate must only act through target and may be called more than once.
+//
+// If mutate returns ErrDeleteDocument the document is deleted instead, or
+// ErrNothingToDelete is returned if it doesn't exist. If mutate returns any
+// other error the update is abandoned and that error is returned.
+//
+// Example:
+//
+//	doc := &MyDoc{}
+//	meta, err := db.Update("doc-1", doc, func() error {
+//		doc.Count++
+//		return nil
+//	})
+func (d *Database) Update(documentID string, target interface{}, mutate func() error) (*DocumentMeta, error) {
+	return d.UpdateWithOptions(documentID, target, mutate, DefaultUpdateOptions)
+}
+
+// UpdateWithOptions is like Update, with configurable retries and the option
+// of creating the document if it doesn't exist.
+func (d *Database) UpdateWithOptions(documentID string, target interface{}, mutate func() error,
+	options UpdateOptions) (*DocumentMeta, error) {
+
+	value := reflect.ValueOf(target)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return nil, errors.New("update target must be a non-nil pointer")
+	}
+
+	backoff := options.Backoff
+	var err error
+	for attempt := 1; ; attempt++ {
+		var meta *DocumentMeta
+		meta, err = d.updateOnce(documentID, value, mutate, options.CreateIfMissing)
+		if !isConflict(err) || attempt >= options.MaxAttempts {
+			return meta, err
+		}
+
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+// updateOnce runs a single read-modify-write cycle.
+func (d *Database) updateOnce(documentID string, target reflect.Value, mutate func() error,
+	createIfMissing bool) (*DocumentMeta, error) {
+
+	// Start from a zero value, so fields missing from the fetched revision
+	// aren't carried over from a previous attempt.
+	target.Elem().Set(reflect.Zero(target.Elem().Type()))
+
+	var current json.RawMessage
+	rev := ""
+	err := d.Get(documentID, &getQuery{}, &current)
+	if err == nil {
+		meta := &struct {
+			Rev string `json:"_rev"`
+		}{}
+		if err = json.Unmarshal(current, meta); err != nil {
+			return nil, err
+		}
+		rev = meta.Rev
+
+		if err = json.Unmarshal(current, target.Interface()); err != nil {
+			return nil, err
+		}
+	} else if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 || !createIfMissing {
+		return nil, err
+	}
+
+	err = mutate()
+	if err == ErrDeleteDocument {
+		if rev == "" {
+			return nil, ErrNothingToDelete
+		}
+		return d.deleteDoc(documentID, rev)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return d.Put(documentID, target.Interface(), NewPutQuery().Rev(rev).Build())
+}
+
+// isConflict reports whether err is a 409 conflict.
+func isConflict(err error) bool {
+	dberr, ok := err.(*CouchError)
+	return ok && dberr.StatusCode == 409
+}
@@ -0,0 +1,119 @@
+package cloudant
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type updateTestDoc struct {
+	ID    string `json:"_id,omitempty"`
+	Rev   string `json:"_rev,omitempty"`
+	Count int    `json:"count"`
+}
+
+func TestDatabase_Update(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	doc := &updateTestDoc{}
+	_, err = database.Update("counter", doc, func() error {
+		doc.Count++
+		return nil
+	})
+	if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 {
+		t.Errorf("expected a 404 error, got %v", err)
+	}
+
+	options := DefaultUpdateOptions
+	options.CreateIfMissing = true
+	options.MaxAttempts = 50
+	options.Backoff = 10 * time.Millisecond
+
+	// Concurrent updates conflict, and must be retried until all are applied
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			doc := &updateTestDoc{}
+			_, err := database.UpdateWithOptions("counter", doc, func() error {
+				doc.Count++
+				return nil
+			}, options)
+			if err != nil {
+				t.Errorf("%s", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	err = database.Get("counter", NewGetQuery().Build(), doc)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if doc.Count != 5 || !strings.HasPrefix(doc.Rev, "5-") {
+		t.Errorf("unexpected document %+v", doc)
+	}
+
+	meta, err := database.Update("counter", doc, func() error {
+		return ErrDeleteDocument
+	})
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	if !strings.HasPrefix(meta.Rev, "6-") {
+		t.Errorf("unexpected tombstone revision %s", meta.Rev)
+	}
+
+	err = database.Get("counter", NewGetQuery().Build(), doc)
+	if dberr, ok := err.(*CouchError); !ok || dberr.StatusCode != 404 {
+		t.Errorf("expected deleted document, got %v", err)
+	}
+}
+
+func TestDatabase_UpdateDeleteMissing(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/_session" {
+			return
+		}
+		if r.Method != "GET" {
+			t.Errorf("unexpected %s request", r.Method)
+		}
+		w.WriteHeader(404)
+		fmt.Fprint(w, `{"error":"not_found","reason":"missing"}`)
+	}))
+	defer server.Close()
+
+	client, err := CreateClientWithRetry("user", "pass", server.URL, 1, 0, 0, 1)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer client.Stop()
+
+	database, err := client.Get("db")
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	options := DefaultUpdateOptions
+	options.CreateIfMissing = true
+
+	doc := &updateTestDoc{}
+	meta, err := database.UpdateWithOptions("doc-1", doc, func() error {
+		return ErrDeleteDocument
+	}, options)
+	if err != ErrNothingToDelete || meta != nil {
+		t.Errorf("unexpected result %+v %v", meta, err)
+	}
+}
@@ -0,0 +1,184 @@
+package cloudant
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ViewRow represents a row in the json array returned by a MapReduce view.
+// Key, Value and Doc are left as raw JSON so that they can be decoded into
+// whichever type the caller expects. ID is empty for reduced rows.
+type ViewRow struct {
+	ID    string          `json:"id"`
+	Key   json.RawMessage `json:"key"`
+	Value json.RawMessage `json:"value"`
+	Doc   json.RawMessage `json:"doc"`
+	Error string          `json:"error"` // Only present for keys that were not found
+}
+
+// ViewStats is the value of a row reduced by the built-in _stats function
+type ViewStats struct {
+	Sum    float64 `json:"sum"`
+	Count  int64   `json:"count"`
+	Min    float64 `json:"min"`
+	Max    float64 `json:"max"`
+	SumSqr float64 `json:"sumsqr"`
+}
+
+// ViewResult is the result of a single query of a multi-query view request.
+// If the query failed, Error and Reason describe why.
+type ViewResult struct {
+	TotalRows int       `json:"total_rows"`
+	Offset    int       `json:"offset"`
+	Rows      []ViewRow `json:"rows"`
+	Error     string    `json:"error,omitempty"`
+	Reason    string    `json:"reason,omitempty"`
+}
+
+// designDocID returns the full document ID of a design document, accepting
+// names both with and without the "_design/" prefix.
+func designDocID(ddoc string) string {
+	return "_design/" + strings.TrimPrefix(ddoc, "_design/")
+}
+
+// Count returns the value of a row reduced by the built-in _count function.
+func (r *ViewRow) Count() (int64, error) {
+	var count int64
+	err := json.Unmarshal(r.Value, &count)
+	return count, err
+}
+
+// Sum returns the value of a row reduced by the built-in _sum function.
+func (r *ViewRow) Sum() (float64, error) {
+	var sum float64
+	err := json.Unmarshal(r.Value, &sum)
+	return sum, err
+}
+
+// Stats returns the value of a row reduced by the built-in _stats function.
+func (r *ViewRow) Stats() (*ViewStats, error) {
+	stats := &ViewStats{}
+	err := json.Unmarshal(r.Value, stats)
+	return stats, err
+}
+
+// View returns a channel in which ViewRow types can be received.
+// See: https://console.bluemix.net/docs/services/Cloudant/api/using_views.html
+func (d *Database) View(ddoc, view string, args *viewQuery) (<-chan *ViewRow, error) {
+	verb := "GET"
+	var body []byte
+	var err error
+	if len(args.Keys) > 0 {
+		// If we're given a "Keys" argument, we're better off with a POST
+		body, err = json.Marshal(map[string][]interface{}{"keys": args.Keys})
+		if err != nil {
+			return nil, err
+		}
+		verb = "POST"
+		args.Keys = nil
+	}
+
+	params, err := args.GetQuery()
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*d.URL, designDocID(ddoc)+"/_view/"+view, params)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request(verb, urlStr, bytes.NewReader(body))
+	if err != nil {
+		if job != nil {
+			job.Close() // close the body reader to avoid leakage
+		}
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		job.Close() // close the body reader to avoid leakage
+		return nil, err
+	}
+
+	results := make(chan *ViewRow, 1000)
+
+	go func(job *Job, results chan<- *ViewRow) {
+		defer job.Close()
+		defer close(results)
+
+		reader := bufio.NewReader(job.response.Body)
+
+		for {
+			line, err := reader.ReadBytes('\n')
+			if err != nil {
+				return
+			}
+			lineStr := string(line)
+			lineStr = strings.TrimSpace(lineStr)      // remove whitespace
+			lineStr = strings.TrimRight(lineStr, ",") // remove trailing comma
+
+			// Mapped rows start with the document ID, reduced rows with the key
+			if strings.HasPrefix(lineStr, "{\"id\":") || strings.HasPrefix(lineStr, "{\"key\":") {
+				var result = new(ViewRow)
+
+				err := json.Unmarshal([]byte(lineStr), result)
+				if err == nil {
+					results <- result
+				}
+			}
+		}
+	}(job, results)
+
+	return results, nil
+}
+
+// ViewQueries sends several queries of the same view in a single request,
+// taking up a single worker rather than one per query. It returns one
+// ViewResult per query, in the order the queries were given.
+// See: http://docs.couchdb.org/en/stable/api/ddoc/views.html#sending-multiple-queries-to-a-view
+func (d *Database) ViewQueries(ddoc, view string, queries []*viewQuery) ([]*ViewResult, error) {
+	objects := make([]map[string]interface{}, len(queries))
+	for i, query := range queries {
+		objects[i] = query.queryObject()
+	}
+
+	body, err := json.Marshal(map[string]interface{}{"queries": objects})
+	if err != nil {
+		return nil, err
+	}
+
+	urlStr, err := Endpoint(*d.URL, designDocID(ddoc)+"/_view/"+view+"/queries", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	job, err := d.client.request("POST", urlStr, bytes.NewReader(body))
+	defer job.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	err = expectedReturnCodes(job, 200)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &struct {
+		Results []*ViewResult `json:"results"`
+	}{}
+	err = json.NewDecoder(job.response.Body).Decode(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Results) != len(queries) {
+		return nil, fmt.Errorf("unexpected result count: %d, expected: %d", len(resp.Results), len(queries))
+	}
+
+	return resp.Results, nil
+}
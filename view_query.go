@@ -0,0 +1,349 @@
+package cloudant
+
+// QueryBuilder implementation for the View() API call.
+//
+// Example:
+// 	query := NewViewQuery().
+//     StartKey([]interface{}{"2018", "01"}).
+//     Group().
+//     Build()
+//
+//	rows, err := db.View("mydesigndoc", "myview", query)
+
+import (
+	"encoding/json"
+	"net/url"
+	"strconv"
+)
+
+// ViewQueryBuilder defines the available parameter-setting functions.
+type ViewQueryBuilder interface {
+	Conflicts() ViewQueryBuilder
+	Descending() ViewQueryBuilder
+	EndKey(interface{}) ViewQueryBuilder
+	EndKeyDocID(string) ViewQueryBuilder
+	Group() ViewQueryBuilder
+	GroupLevel(int) ViewQueryBuilder
+	IncludeDocs() ViewQueryBuilder
+	InclusiveEnd(bool) ViewQueryBuilder
+	Key(interface{}) ViewQueryBuilder
+	Keys([]interface{}) ViewQueryBuilder
+	Limit(int) ViewQueryBuilder
+	Reduce(bool) ViewQueryBuilder
+	Skip(int) ViewQueryBuilder
+	Stable() ViewQueryBuilder
+	Stale(string) ViewQueryBuilder
+	StartKey(interface{}) ViewQueryBuilder
+	StartKeyDocID(string) ViewQueryBuilder
+	Update(string) ViewQueryBuilder
+	Build() *viewQuery
+}
+
+type viewQueryBuilder struct {
+	conflicts     bool
+	descending    bool
+	endKey        interface{}
+	endKeyDocID   string
+	group         bool
+	groupLevel    int
+	includeDocs   bool
+	inclusiveEnd  *bool
+	key           interface{}
+	keys          []interface{}
+	limit         int
+	reduce        *bool
+	skip          int
+	stable        bool
+	stale         string
+	startKey      interface{}
+	startKeyDocID string
+	update        string
+}
+
+// viewQuery holds the implemented API call parameters. Keys are JSON-encoded
+// by GetQuery(), so any JSON-serialisable value (strings, numbers, arrays,
+// objects) can be used as a key.
+type viewQuery struct {
+	Conflicts     bool
+	Descending    bool
+	EndKey        interface{}
+	EndKeyDocID   string
+	Group         bool
+	GroupLevel    int
+	IncludeDocs   bool
+	InclusiveEnd  *bool
+	Key           interface{}
+	Keys          []interface{}
+	Limit         int
+	Reduce        *bool
+	Skip          int
+	Stable        bool
+	Stale         string
+	StartKey      interface{}
+	StartKeyDocID string
+	Update        string
+}
+
+// NewViewQuery is the entry point.
+func NewViewQuery() ViewQueryBuilder {
+	return &viewQueryBuilder{}
+}
+
+func (v *viewQueryBuilder) Conflicts() ViewQueryBuilder {
+	v.conflicts = true
+	return v
+}
+
+func (v *viewQueryBuilder) Descending() ViewQueryBuilder {
+	v.descending = true
+	return v
+}
+
+func (v *viewQueryBuilder) EndKey(endKey interface{}) ViewQueryBuilder {
+	v.endKey = endKey
+	return v
+}
+
+func (v *viewQueryBuilder) EndKeyDocID(docID string) ViewQueryBuilder {
+	v.endKeyDocID = docID
+	return v
+}
+
+func (v *viewQueryBuilder) Group() ViewQueryBuilder {
+	v.group = true
+	return v
+}
+
+func (v *viewQueryBuilder) GroupLevel(level int) ViewQueryBuilder {
+	v.groupLevel = level
+	return v
+}
+
+func (v *viewQueryBuilder) IncludeDocs() ViewQueryBuilder {
+	v.includeDocs = true
+	return v
+}
+
+func (v *viewQueryBuilder) InclusiveEnd(inclusiveEnd bool) ViewQueryBuilder {
+	v.inclusiveEnd = &inclusiveEnd
+	return v
+}
+
+func (v *viewQueryBuilder) Key(key interface{}) ViewQueryBuilder {
+	v.key = key
+	return v
+}
+
+func (v *viewQueryBuilder) Keys(keys []interface{}) ViewQueryBuilder {
+	v.keys = keys
+	return v
+}
+
+func (v *viewQueryBuilder) Limit(lim int) ViewQueryBuilder {
+	v.limit = lim
+	return v
+}
+
+func (v *viewQueryBuilder) Reduce(reduce bool) ViewQueryBuilder {
+	v.reduce = &reduce
+	return v
+}
+
+func (v *viewQueryBuilder) Skip(skip int) ViewQueryBuilder {
+	v.skip = skip
+	return v
+}
+
+func (v *viewQueryBuilder) Stable() ViewQueryBuilder {
+	v.stable = true
+	return v
+}
+
+// Stale is deprecated in CouchDB 2.X in favour of Stable() and Update(), but
+// is still accepted by Cloudant. Valid values are "ok" and "update_after".
+func (v *viewQueryBuilder) Stale(stale string) ViewQueryBuilder {
+	v.stale = stale
+	return v
+}
+
+func (v *viewQueryBuilder) StartKey(startKey interface{}) ViewQueryBuilder {
+	v.startKey = startKey
+	return v
+}
+
+func (v *viewQueryBuilder) StartKeyDocID(docID string) ViewQueryBuilder {
+	v.startKeyDocID = docID
+	return v
+}
+
+// Update controls whether the view is brought up to date before the response
+// is sent. Valid values are "true", "false" and "lazy".
+func (v *viewQueryBuilder) Update(update string) ViewQueryBuilder {
+	v.update = update
+	return v
+}
+
+// GetQuery implements the QueryBuilder interface. It returns an
+// url.Values map with the non-default values set.
+func (vq *viewQuery) GetQuery() (url.Values, error) {
+	vals := url.Values{}
+
+	if vq.Conflicts {
+		vals.Set("conflicts", "true")
+	}
+	if vq.Descending {
+		vals.Set("descending", "true")
+	}
+	if vq.EndKey != nil {
+		data, err := json.Marshal(vq.EndKey)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("endkey", string(data[:]))
+	}
+	if vq.EndKeyDocID != "" {
+		vals.Set("endkey_docid", vq.EndKeyDocID)
+	}
+	if vq.Group {
+		vals.Set("group", "true")
+	}
+	if vq.GroupLevel > 0 {
+		vals.Set("group_level", strconv.Itoa(vq.GroupLevel))
+	}
+	if vq.IncludeDocs {
+		vals.Set("include_docs", "true")
+	}
+	if vq.InclusiveEnd != nil {
+		vals.Set("inclusive_end", strconv.FormatBool(*vq.InclusiveEnd))
+	}
+	if vq.Key != nil {
+		data, err := json.Marshal(vq.Key)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("key", string(data[:]))
+	}
+	if len(vq.Keys) > 0 {
+		data, err := json.Marshal(vq.Keys)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("keys", string(data[:]))
+	}
+	if vq.Limit > 0 {
+		vals.Set("limit", strconv.Itoa(vq.Limit))
+	}
+	if vq.Reduce != nil {
+		vals.Set("reduce", strconv.FormatBool(*vq.Reduce))
+	}
+	if vq.Skip > 0 {
+		vals.Set("skip", strconv.Itoa(vq.Skip))
+	}
+	if vq.Stable {
+		vals.Set("stable", "true")
+	}
+	if vq.Stale != "" {
+		vals.Set("stale", vq.Stale)
+	}
+	if vq.StartKey != nil {
+		data, err := json.Marshal(vq.StartKey)
+		if err != nil {
+			return nil, err
+		}
+		vals.Set("startkey", string(data[:]))
+	}
+	if vq.StartKeyDocID != "" {
+		vals.Set("startkey_docid", vq.StartKeyDocID)
+	}
+	if vq.Update != "" {
+		vals.Set("update", vq.Update)
+	}
+
+	return vals, nil
+}
+
+// queryObject returns the non-default parameters as a JSON object, as used by
+// the multi-query API.
+func (vq *viewQuery) queryObject() map[string]interface{} {
+	obj := map[string]interface{}{}
+
+	if vq.Conflicts {
+		obj["conflicts"] = true
+	}
+	if vq.Descending {
+		obj["descending"] = true
+	}
+	if vq.EndKey != nil {
+		obj["endkey"] = vq.EndKey
+	}
+	if vq.EndKeyDocID != "" {
+		obj["endkey_docid"] = vq.EndKeyDocID
+	}
+	if vq.Group {
+		obj["group"] = true
+	}
+	if vq.GroupLevel > 0 {
+		obj["group_level"] = vq.GroupLevel
+	}
+	if vq.IncludeDocs {
+		obj["include_docs"] = true
+	}
+	if vq.InclusiveEnd != nil {
+		obj["inclusive_end"] = *vq.InclusiveEnd
+	}
+	if vq.Key != nil {
+		obj["key"] = vq.Key
+	}
+	if len(vq.Keys) > 0 {
+		obj["keys"] = vq.Keys
+	}
+	if vq.Limit > 0 {
+		obj["limit"] = vq.Limit
+	}
+	if vq.Reduce != nil {
+		obj["reduce"] = *vq.Reduce
+	}
+	if vq.Skip > 0 {
+		obj["skip"] = vq.Skip
+	}
+	if vq.Stable {
+		obj["stable"] = true
+	}
+	if vq.Stale != "" {
+		obj["stale"] = vq.Stale
+	}
+	if vq.StartKey != nil {
+		obj["startkey"] = vq.StartKey
+	}
+	if vq.StartKeyDocID != "" {
+		obj["startkey_docid"] = vq.StartKeyDocID
+	}
+	if vq.Update != "" {
+		obj["update"] = vq.Update
+	}
+
+	return obj
+}
+
+func (v *viewQueryBuilder) Build() *viewQuery {
+	return &viewQuery{
+		Conflicts:     v.conflicts,
+		Descending:    v.descending,
+		EndKey:        v.endKey,
+		EndKeyDocID:   v.endKeyDocID,
+		Group:         v.group,
+		GroupLevel:    v.groupLevel,
+		IncludeDocs:   v.includeDocs,
+		InclusiveEnd:  v.inclusiveEnd,
+		Key:           v.key,
+		Keys:          v.keys,
+		Limit:         v.limit,
+		Reduce:        v.reduce,
+		Skip:          v.skip,
+		Stable:        v.stable,
+		Stale:         v.stale,
+		StartKey:      v.startKey,
+		StartKeyDocID: v.startKeyDocID,
+		Update:        v.update,
+	}
+}
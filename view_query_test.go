@@ -0,0 +1,103 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func TestViewQuery_Args(t *testing.T) {
+	// Conflicts     bool
+	// Descending    bool
+	// EndKey        interface{}
+	// EndKeyDocID   string
+	// GroupLevel    int
+	// IncludeDocs   bool
+	// InclusiveEnd  *bool
+	// Limit         int
+	// Reduce        *bool
+	// Skip          int
+	// Stable        bool
+	// StartKey      interface{}
+	// StartKeyDocID string
+	// Update        string
+
+	expectedQueryStrings := []string{
+		"conflicts=true",
+		"descending=true",
+		"endkey=%5B%222018%22%2C%7B%7D%5D",
+		"endkey_docid=doc-999",
+		"group_level=2",
+		"include_docs=true",
+		"inclusive_end=false",
+		"limit=5",
+		"reduce=false",
+		"skip=32",
+		"stable=true",
+		"startkey=%5B%222018%22%5D",
+		"startkey_docid=doc-001",
+		"update=lazy",
+	}
+
+	query := NewViewQuery().
+		Conflicts().
+		Descending().
+		EndKey([]interface{}{"2018", map[string]interface{}{}}).
+		EndKeyDocID("doc-999").
+		GroupLevel(2).
+		IncludeDocs().
+		InclusiveEnd(false).
+		Limit(5).
+		Reduce(false).
+		Skip(32).
+		Stable().
+		StartKey([]string{"2018"}).
+		StartKeyDocID("doc-001").
+		Update("lazy").
+		Build()
+
+	values, _ := query.GetQuery()
+	queryString := values.Encode()
+
+	for _, str := range expectedQueryStrings {
+		if !strings.Contains(queryString, str) {
+			t.Errorf("parameter encoding not found '%s' in '%s'", str, queryString)
+			return
+		}
+	}
+}
+
+func TestViewQuery_Key(t *testing.T) {
+	query := NewViewQuery().
+		Key(42).
+		Stale("ok").
+		Build()
+
+	values, _ := query.GetQuery()
+
+	if values.Get("key") != "42" {
+		t.Errorf("unexpected key encoding '%s'", values.Get("key"))
+	}
+	if values.Get("stale") != "ok" {
+		t.Errorf("unexpected stale encoding '%s'", values.Get("stale"))
+	}
+	if _, ok := values["reduce"]; ok {
+		t.Error("reduce should be omitted unless explicitly set")
+	}
+}
+
+func TestViewQuery_QueryObject(t *testing.T) {
+	query := NewViewQuery().
+		Keys([]interface{}{"doc-001", "doc-002"}).
+		Reduce(false).
+		StartKeyDocID("123").
+		Limit(5).
+		Build()
+
+	data, _ := json.Marshal(query.queryObject())
+	expected := `{"keys":["doc-001","doc-002"],"limit":5,"reduce":false,"startkey_docid":"123"}`
+
+	if string(data) != expected {
+		t.Errorf("unexpected query object '%s'", data)
+	}
+}
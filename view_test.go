@@ -0,0 +1,146 @@
+package cloudant
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+)
+
+func TestViewRow_Reduced(t *testing.T) {
+	count := &ViewRow{}
+	if err := json.Unmarshal([]byte(`{"key":null,"value":1000}`), count); err != nil {
+		t.Fatal(err)
+	}
+	if c, err := count.Count(); err != nil || c != 1000 {
+		t.Errorf("unexpected _count value %d (%v)", c, err)
+	}
+
+	sum := &ViewRow{}
+	if err := json.Unmarshal([]byte(`{"key":["a"],"value":12.5}`), sum); err != nil {
+		t.Fatal(err)
+	}
+	if s, err := sum.Sum(); err != nil || s != 12.5 {
+		t.Errorf("unexpected _sum value %f (%v)", s, err)
+	}
+
+	stats := &ViewRow{}
+	data := []byte(`{"key":null,"value":{"sum":6,"count":3,"min":1,"max":3,"sumsqr":14}}`)
+	if err := json.Unmarshal(data, stats); err != nil {
+		t.Fatal(err)
+	}
+	st, err := stats.Stats()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if st.Sum != 6 || st.Count != 3 || st.Min != 1 || st.Max != 3 || st.SumSqr != 14 {
+		t.Errorf("unexpected _stats value %+v", st)
+	}
+}
+
+func TestDatabase_View(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	makeDocuments(database, 100)
+
+	ddoc := map[string]interface{}{
+		"_id": "_design/test",
+		"views": map[string]interface{}{
+			"bar": map[string]string{
+				"map":    "function(doc) { if (doc.bar) { emit(doc._id, doc.bar); } }",
+				"reduce": "_sum",
+			},
+		},
+	}
+	if _, err = database.Set(ddoc); err != nil {
+		t.Fatalf("failed to create design document: %s", err)
+	}
+
+	query := NewViewQuery().
+		StartKey("doc-010").
+		EndKey("doc-019").
+		Reduce(false).
+		IncludeDocs().
+		Build()
+
+	rows, err := database.View("test", "bar", query)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	i := 0
+	for row := range rows {
+		i++
+		doc := &cloudantDocument{}
+		if err := json.Unmarshal(row.Doc, doc); err != nil || doc.ID != row.ID {
+			t.Errorf("unexpected doc for row %s", row.ID)
+		}
+	}
+	if 10 != i {
+		t.Errorf("unexpected number of rows received %d", i)
+	}
+
+	rows, err = database.View("_design/test", "bar", NewViewQuery().Build())
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	row, more := <-rows
+	if !more {
+		t.Fatal("missing reduced row")
+	}
+	if sum, err := row.Sum(); err != nil || sum != 100*123 {
+		t.Errorf("unexpected reduced value %f (%v)", sum, err)
+	}
+}
+
+func TestDatabase_ViewQueries(t *testing.T) {
+	database, err := makeDatabase()
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+	defer func() {
+		fmt.Printf("Deleting database %s", database.Name)
+		database.client.Delete(database.Name)
+	}()
+
+	makeDocuments(database, 100)
+
+	ddoc := NewDesignDocument("test")
+	ddoc.Views = map[string]ViewDefinition{
+		"bar": {Map: "function(doc) { emit(doc._id, doc.bar); }", Reduce: "_count"},
+	}
+	if _, err = database.PutDesignDoc(ddoc); err != nil {
+		t.Fatalf("failed to create design document: %s", err)
+	}
+
+	queries := []*viewQuery{
+		NewViewQuery().Keys([]interface{}{"doc-001", "doc-002"}).Reduce(false).Build(),
+		NewViewQuery().StartKey("doc-010").EndKey("doc-019").Reduce(false).Build(),
+		NewViewQuery().Build(),
+	}
+
+	results, err := database.ViewQueries("test", "bar", queries)
+	if err != nil {
+		t.Fatalf("%s", err)
+	}
+
+	if len(results) != 3 {
+		t.Fatalf("unexpected number of results %d", len(results))
+	}
+	if len(results[0].Rows) != 2 || results[0].Rows[1].ID != "doc-002" {
+		t.Errorf("unexpected first result %+v", results[0])
+	}
+	if len(results[1].Rows) != 10 {
+		t.Errorf("unexpected number of rows in second result %d", len(results[1].Rows))
+	}
+	if count, _ := results[2].Rows[0].Count(); count != 100 {
+		t.Errorf("unexpected reduced value in third result %d", count)
+	}
+}